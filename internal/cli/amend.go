@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/shichao402/github-issue-pack/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var amendCmd = &cobra.Command{
+	Use:   "amend <issue-number>",
+	Short: "修改已创建 Issue 的 Issue 包",
+	Long: `修改已创建 Issue 的 payload 或附件。
+
+更新 payload 所在的 Gist 生成新版本，旧版本保留在 Gist 历史中，
+并在 Issue 下发表评论列出变更的字段和附件。--payload 完整替换 payload，
+并按类型 schema 和目标仓库 manifest 重新校验；--attach 新增或覆盖同名附件。
+
+示例:
+  github-issue amend 123 --repo owner/repo --payload request.json
+  github-issue amend 123 --repo owner/repo --attach crash.log --comment "补充崩溃日志"
+  github-issue amend 123 --repo owner/repo --remove-attachment old.log --dry-run
+
+使用 "github-issue get 123 --diff" 查看变更，"--revision" 查看旧版本。`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAmend,
+}
+
+var (
+	amendRepo    string
+	amendPayload string
+	amendAttach  []string
+	amendRemove  []string
+	amendComment string
+	amendDryRun  bool
+)
+
+func init() {
+	rootCmd.AddCommand(amendCmd)
+
+	amendCmd.Flags().StringVar(&amendRepo, "repo", "", "目标仓库 (owner/repo)")
+	amendCmd.Flags().StringVar(&amendPayload, "payload", "", "新的 payload 文件路径 (JSON 或 .yaml/.yml)")
+	amendCmd.Flags().StringSliceVar(&amendAttach, "attach", nil, "新增或覆盖的附件文件路径")
+	amendCmd.Flags().StringSliceVar(&amendRemove, "remove-attachment", nil, "删除的附件名")
+	amendCmd.Flags().StringVar(&amendComment, "comment", "", "附在变更评论中的说明")
+	amendCmd.Flags().BoolVar(&amendDryRun, "dry-run", false, "预览模式，只输出修改后的 Issue 包")
+
+	amendCmd.MarkFlagRequired("repo")
+}
+
+func runAmend(cmd *cobra.Command, args []string) error {
+	in := opInput{
+		"repo":               amendRepo,
+		"number":             args[0],
+		"remove_attachments": amendRemove,
+		"comment":            amendComment,
+		"dry_run":            amendDryRun,
+	}
+
+	if amendPayload != "" {
+		payload, err := readPayloadFile(amendPayload)
+		if err != nil {
+			return err
+		}
+		in["payload"] = payload
+	}
+
+	var attachments []models.Attachment
+	for _, path := range amendAttach {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("读取附件失败 %s: %w", path, err)
+		}
+		attachments = append(attachments, models.Attachment{
+			Name:    path,
+			Content: string(data),
+		})
+	}
+	in["attachments"] = attachments
+
+	return runOperation(cmd, "amend", in, "text")
+}
@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// maxBulkConcurrency 批量操作的最大并发数，避免触发 GitHub 的二级限流
+const maxBulkConcurrency = 16
+
+// bulkFlags update/close 共用的批量操作参数
+type bulkFlags struct {
+	query       string
+	yes         bool
+	concurrency int
+	dryRun      bool
+	format      string
+}
+
+func (f *bulkFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.query, "query", "", `按条件批量选择 Issue，如 "status:processing type:pack-sync older:7d"（最多 1000 个）`)
+	cmd.Flags().BoolVarP(&f.yes, "yes", "y", false, "批量操作时跳过确认")
+	cmd.Flags().IntVar(&f.concurrency, "concurrency", 4, fmt.Sprintf("批量操作的并发数 (1~%d)", maxBulkConcurrency))
+	cmd.Flags().BoolVar(&f.dryRun, "dry-run", false, "批量操作时只列出将处理的 Issue")
+	formatFlag(cmd.Flags(), &f.format, "批量操作结果的输出格式", "table", "json")
+}
+
+// bulkArgs 校验位置参数：--query 时不接受编号，否则至少一个编号或 "-"（从标准输入读取）
+func bulkArgs(f *bulkFlags) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if f.query != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("--query 不能与 Issue 编号同时使用")
+			}
+			return nil
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("需要 Issue 编号、\"-\"（从标准输入读取编号）或 --query")
+		}
+		return nil
+	}
+}
+
+// isBulk 是否为批量操作：--query、--dry-run、多个编号或从标准输入读取编号
+func (f *bulkFlags) isBulk(args []string) bool {
+	return f.query != "" || f.dryRun || len(args) != 1 || args[0] == "-"
+}
+
+// bulkTarget 批量操作的一个 Issue；从编号列表选择时只有编号
+type bulkTarget struct {
+	Number int
+	Title  string
+	Type   string
+	Status string
+}
+
+// bulkItem 单个 Issue 的处理结果
+type bulkItem struct {
+	Number int    `json:"number"`
+	OK     bool   `json:"ok"`
+	Error  string `json:"error,omitempty"`
+}
+
+// bulkReport 批量操作的结果
+type bulkReport struct {
+	Operation string     `json:"operation"`
+	Succeeded int        `json:"succeeded"`
+	Failed    int        `json:"failed"`
+	Items     []bulkItem `json:"items"`
+}
+
+// runBulkOperation 对选中的 Issue 逐个执行操作
+//
+// 先用第一个 Issue 校验参数，列出将处理的 Issue 并确认，再以有限并发执行；
+// 单个 Issue 失败不影响其他 Issue，有失败时返回错误（退出码非 0）。
+func runBulkOperation(cmd *cobra.Command, name string, in opInput, args []string, f *bulkFlags, action string) error {
+	if f.concurrency < 1 || f.concurrency > maxBulkConcurrency {
+		return fmt.Errorf("--concurrency 必须在 1~%d 之间: %d", maxBulkConcurrency, f.concurrency)
+	}
+	if f.format != "table" && f.format != "json" {
+		return fmt.Errorf("无效的输出格式: %s (table/json)", f.format)
+	}
+
+	svc := newIssueService(cmd)
+	repo := in.str("repo")
+	targets, fromStdin, err := selectBulkTargets(svc, repo, args, f.query)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Fprintln(os.Stderr, "没有匹配的 Issue")
+		return nil
+	}
+
+	op := findOperation(name)
+	probe := cloneInput(in)
+	probe["number"] = targets[0].Number
+	if err := op.validate(probe); err != nil {
+		return err
+	}
+
+	printBulkSummary(os.Stderr, targets, action)
+	if f.dryRun {
+		fmt.Fprintln(os.Stderr, "=== Dry Run 模式，未做任何修改 ===")
+		return nil
+	}
+	if !f.yes {
+		ok, err := confirmBulk(fmt.Sprintf("确认对以上 %d 个 Issue 执行?", len(targets)), fromStdin)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(os.Stderr, "已取消")
+			return nil
+		}
+	}
+
+	report := &bulkReport{Operation: name, Items: make([]bulkItem, len(targets))}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+		sem  = make(chan struct{}, f.concurrency)
+	)
+	for i, t := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i, number int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			item := cloneInput(in)
+			item["number"] = number
+			result := bulkItem{Number: number, OK: true}
+			if _, err := op.Run(context.Background(), svc, item); err != nil {
+				result.OK, result.Error = false, err.Error()
+			}
+			report.Items[i] = result
+
+			mu.Lock()
+			done++
+			mark := "✅"
+			if !result.OK {
+				mark = "❌"
+			}
+			fmt.Fprintf(os.Stderr, "[%d/%d] #%d %s\n", done, len(targets), number, mark)
+			mu.Unlock()
+		}(i, t.Number)
+	}
+	wg.Wait()
+
+	for _, item := range report.Items {
+		if item.OK {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+	}
+	printBulkReport(report, f.format)
+
+	if report.Failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d 个 Issue 处理失败", report.Failed)
+	}
+	return nil
+}
+
+// selectBulkTargets 按 --query、标准输入或位置参数选择 Issue，编号去重并保持原顺序
+func selectBulkTargets(svc *service.IssueService, repo string, args []string, query string) ([]bulkTarget, bool, error) {
+	if query != "" {
+		q, err := service.ParseBulkQuery(query)
+		if err != nil {
+			return nil, false, err
+		}
+		issues, err := svc.SelectIssues(repo, q)
+		if err != nil {
+			return nil, false, err
+		}
+		targets := make([]bulkTarget, len(issues))
+		for i, issue := range issues {
+			targets[i] = bulkTarget{Number: issue.Number, Title: issue.Title, Type: issue.Type, Status: issue.Status}
+		}
+		return targets, false, nil
+	}
+
+	fromStdin := false
+	var fields []string
+	for _, arg := range args {
+		if arg != "-" {
+			fields = append(fields, arg)
+			continue
+		}
+		fromStdin = true
+		stdinFields, err := readNumberFields(os.Stdin)
+		if err != nil {
+			return nil, false, err
+		}
+		fields = append(fields, stdinFields...)
+	}
+
+	var targets []bulkTarget
+	seen := map[int]bool{}
+	for _, field := range fields {
+		n, err := strconv.Atoi(strings.TrimPrefix(field, "#"))
+		if err != nil || n <= 0 {
+			return nil, false, fmt.Errorf("无效的 Issue 编号: %s", field)
+		}
+		if !seen[n] {
+			seen[n] = true
+			targets = append(targets, bulkTarget{Number: n})
+		}
+	}
+	return targets, fromStdin, nil
+}
+
+// readNumberFields 读取以空白或逗号分隔的 Issue 编号，忽略 # 开头的注释行
+func readNumberFields(r io.Reader) ([]string, error) {
+	var fields []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || isCommentLine(line) {
+			continue
+		}
+		fields = append(fields, strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取标准输入失败: %w", err)
+	}
+	return fields, nil
+}
+
+// isCommentLine "#" 后不是数字的行为注释，"#123" 仍视为编号
+func isCommentLine(line string) bool {
+	rest := strings.TrimPrefix(line, "#")
+	return rest != line && (rest == "" || rest[0] < '0' || rest[0] > '9')
+}
+
+func cloneInput(in opInput) opInput {
+	cp := make(opInput, len(in)+1)
+	for k, v := range in {
+		cp[k] = v
+	}
+	return cp
+}
+
+// printBulkSummary 列出将处理的 Issue
+func printBulkSummary(w io.Writer, targets []bulkTarget, action string) {
+	fmt.Fprintf(w, "以下 %d 个 Issue 将%s:\n", len(targets), action)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, t := range targets {
+		if t.Title == "" {
+			fmt.Fprintf(tw, "  #%d\n", t.Number)
+			continue
+		}
+		fmt.Fprintf(tw, "  #%d\t%s\t%s\t%s\n", t.Number, t.Type, t.Status, shortTitle(t.Title))
+	}
+	tw.Flush()
+}
+
+// confirmBulk 询问是否继续；编号从标准输入读取时改从终端读取回答
+func confirmBulk(prompt string, stdinConsumed bool) (bool, error) {
+	in := io.Reader(os.Stdin)
+	if stdinConsumed {
+		tty, err := os.Open("/dev/tty")
+		if err != nil {
+			return false, fmt.Errorf("无法从终端确认，请使用 --yes")
+		}
+		defer tty.Close()
+		in = tty
+	}
+
+	fmt.Fprintf(os.Stderr, "%s (y/N): ", prompt)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("无法读取确认，请使用 --yes")
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// printBulkReport 输出每个 Issue 的结果和汇总
+func printBulkReport(report *bulkReport, format string) {
+	if format == "json" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Issue\tResult\tError")
+	fmt.Fprintln(w, "-----\t------\t-----")
+	for _, item := range report.Items {
+		result := "ok"
+		if !item.OK {
+			result = "failed"
+		}
+		fmt.Fprintf(w, "#%d\t%s\t%s\n", item.Number, result, item.Error)
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Printf("共 %d 个 Issue：成功 %d 个，失败 %d 个\n", len(report.Items), report.Succeeded, report.Failed)
+}
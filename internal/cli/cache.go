@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "管理本地 API 响应缓存",
+	Long: `管理本地 API 响应缓存。
+
+GET 请求的响应按 ETag/Last-Modified 缓存在本地，再次请求时发送条件请求，
+内容未变化时 GitHub 返回 304，不消耗 API 配额；Gist 的历史版本不可变，
+缓存后不再请求。使用 --no-cache 可在单次命令中跳过缓存。
+
+示例:
+  github-issue cache info
+  github-issue cache clear`,
+}
+
+var cacheInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "显示缓存目录和占用空间",
+	RunE:  runCacheInfo,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "清除全部缓存",
+	RunE:  runCacheClear,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheInfoCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+func openCache() (*github.Cache, error) {
+	dir, err := github.DefaultCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return github.NewCache(dir), nil
+}
+
+func runCacheInfo(cmd *cobra.Command, args []string) error {
+	cache, err := openCache()
+	if err != nil {
+		return err
+	}
+	stats, err := cache.Stats()
+	if err != nil {
+		return fmt.Errorf("读取缓存失败: %w", err)
+	}
+
+	fmt.Printf("缓存目录: %s\n", cache.Dir())
+	fmt.Printf("响应缓存: %d 条\n", stats.Responses)
+	fmt.Printf("Gist 版本: %d 个\n", stats.Revisions)
+	fmt.Printf("占用空间: %.1f KB\n", float64(stats.Bytes)/1024)
+	return nil
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	cache, err := openCache()
+	if err != nil {
+		return err
+	}
+	stats, _ := cache.Stats()
+	if err := cache.Clear(); err != nil {
+		return err
+	}
+	fmt.Printf("✅ 已清除 %d 条响应缓存和 %d 个 Gist 版本\n", stats.Responses, stats.Revisions)
+	return nil
+}
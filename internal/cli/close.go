@@ -2,21 +2,29 @@ package cli
 
 import (
 	"fmt"
-	"strconv"
 
-	"github.com/shichao402/github-issue-pack/internal/service"
 	"github.com/spf13/cobra"
 )
 
 var closeCmd = &cobra.Command{
-	Use:   "close <issue-number>",
+	Use:   "close <issue-number>... | --query <条件>",
 	Short: "关闭并标记 Issue 处理结果",
 	Long: `关闭 Issue 并标记处理结果。
 
 示例:
   github-issue close 123 --repo owner/repo --result success
-  github-issue close 123 --repo owner/repo --result rejected --comment "不符合规范"`,
-	Args: cobra.ExactArgs(1),
+  github-issue close 123 --repo owner/repo --result rejected --comment "不符合规范"
+  github-issue close 123 --repo owner/repo --result duplicate --duplicate-of 98
+
+duplicate 结果在评论中以 "Duplicate of #98" 链接规范 Issue。
+
+批量关闭：传入多个编号、"-"（从标准输入读取编号）或 --query 条件，
+列出将处理的 Issue 并确认后以 --concurrency 并发执行，最后输出每个 Issue 的结果：
+  github-issue close --repo owner/repo --query "status:processing type:pack-sync older:7d" --result success
+  cat numbers.txt | github-issue close - --repo owner/repo --result rejected --yes
+
+--query 支持 status:、type:、older:（超过该时长未更新），其余词作为 GitHub 搜索条件。
+GitHub 搜索最多返回 1000 个结果，匹配更多时报错而不处理，请缩小条件分批处理。`,
 	RunE: runClose,
 }
 
@@ -24,41 +32,41 @@ var (
 	closeRepo    string
 	closeResult  string
 	closeComment string
+	closeDupOf   int
+	closeBulk    bulkFlags
 )
 
 func init() {
 	rootCmd.AddCommand(closeCmd)
 
 	closeCmd.Flags().StringVar(&closeRepo, "repo", "", "目标仓库 (owner/repo)")
-	closeCmd.Flags().StringVar(&closeResult, "result", "", "处理结果 (success/rejected)")
+	closeCmd.Flags().StringVar(&closeResult, "result", "", "处理结果 (success/rejected/duplicate)")
+	closeCmd.Flags().IntVar(&closeDupOf, "duplicate-of", 0, "规范 Issue 编号 (--result duplicate 时必填)")
 	closeCmd.Flags().StringVar(&closeComment, "comment", "", "处理说明")
 
+	closeBulk.register(closeCmd)
+	closeCmd.Args = bulkArgs(&closeBulk)
+
 	closeCmd.MarkFlagRequired("repo")
 	closeCmd.MarkFlagRequired("result")
 }
 
 func runClose(cmd *cobra.Command, args []string) error {
-	token := getToken(cmd)
-
-	number, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("无效的 Issue 编号: %s", args[0])
+	in := opInput{
+		"repo":    closeRepo,
+		"result":  closeResult,
+		"comment": closeComment,
 	}
-
-	// 验证结果
-	if closeResult != "success" && closeResult != "rejected" {
-		return fmt.Errorf("无效的结果: %s，只能是 success 或 rejected", closeResult)
-	}
-
-	svc := service.NewIssueService(token)
-	if err := svc.Close(closeRepo, number, closeResult, closeComment); err != nil {
-		return err
+	if closeDupOf != 0 {
+		in["duplicate_of"] = closeDupOf
 	}
-
-	status := "processed"
-	if closeResult == "rejected" {
-		status = "rejected"
+	if closeBulk.isBulk(args) {
+		// 逐个执行前先检查，避免每个 Issue 都以同样的原因失败
+		if closeResult == "duplicate" && closeDupOf == 0 {
+			return fmt.Errorf("--result duplicate 时必须提供 --duplicate-of")
+		}
+		return runBulkOperation(cmd, "close", in, args, &closeBulk, "以 "+closeResult+" 关闭")
 	}
-	fmt.Printf("✅ Issue #%d 已关闭，状态: %s\n", number, status)
-	return nil
+	in["number"] = args[0]
+	return runOperation(cmd, "close", in, "text")
 }
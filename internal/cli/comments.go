@@ -0,0 +1,11 @@
+package cli
+
+var commentsCmd = operationCommand("comments", "comments <issue-number>", `列出 Issue 的全部评论。
+
+示例:
+  github-issue comments 123 --repo owner/repo
+  github-issue comments 123 --repo owner/repo --format json`, "number")
+
+func init() {
+	rootCmd.AddCommand(commentsCmd)
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/shichao402/github-issue-pack/internal/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "查看配置文件与 profile",
+	Long: `查看配置文件位置和当前生效的 profile。
+
+配置文件示例 (~/.config/github-issue/config.yaml):
+
+  default_profile: work
+  profiles:
+    work:
+      repo: owner/repo
+      token: env:WORK_GITHUB_TOKEN
+      format: json
+      limit: 50
+    enterprise:
+      repo: team/inbox
+      api_host: https://github.example.com/api/v3
+      token: file:~/.config/github-issue/ghe-token
+
+示例:
+  github-issue config show
+  github-issue config show --profile enterprise`,
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "显示配置文件路径和当前生效的 profile",
+	RunE:  runConfigShow,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd)
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("全局配置: %s\n", config.GlobalPath())
+	if path := config.ProjectPath(); path != "" {
+		fmt.Printf("项目配置: %s\n", path)
+	} else {
+		fmt.Println("项目配置: (未找到)")
+	}
+	if names := cfg.ProfileNames(); len(names) > 0 {
+		fmt.Printf("可用 profile: %v\n", names)
+	}
+
+	data, err := yaml.Marshal(activeProfile)
+	if err != nil {
+		return err
+	}
+	fmt.Println("\n--- 当前生效的 profile ---")
+	fmt.Print(string(data))
+	return nil
+}
@@ -1,12 +1,11 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/shichao402/github-issue-pack/internal/models"
-	"github.com/shichao402/github-issue-pack/internal/service"
 	"github.com/spf13/cobra"
 )
 
@@ -17,7 +16,19 @@ var createCmd = &cobra.Command{
 
 示例:
   github-issue create --repo owner/repo --type feature-request --title "添加新功能"
-  github-issue create --repo owner/repo --type bug-report --title "修复问题" --payload request.json`,
+  github-issue create --repo owner/repo --type bug-report --title "修复问题" --payload request.json
+  github-issue create --repo owner/repo --type security-report --title "XSS" --payload report.json
+
+除内置类型外，目标仓库可在 .github/issue-pack/types/*.json 中注册自定义类型，
+create 会按其 schema 校验 payload。可用 "github-issue types --repo owner/repo" 查看。
+
+使用 --interactive 逐项引导填写类型、标题和 payload 字段（长文本打开 $EDITOR），
+预览后确认再上传：
+  github-issue create --repo owner/repo --interactive
+
+创建前会在同类型的打开 Issue 中检查重复（pack-register 比较 repository，
+pack-sync 比较 repository + version，其余类型比较标题和描述），发现时给出提示，
+不阻止创建。使用 --no-duplicate-check 跳过检查。`,
 	RunE: runCreate,
 }
 
@@ -28,53 +39,65 @@ var (
 	createPayload string
 	createAttach  []string
 	createDryRun  bool
+	createWizard  bool
+	createNoMeta  bool
+	createMeta    []string
+	createPackDir string
+	createNoDup   bool
 )
 
 func init() {
 	rootCmd.AddCommand(createCmd)
 
 	createCmd.Flags().StringVar(&createRepo, "repo", "", "目标仓库 (owner/repo)")
-	createCmd.Flags().StringVar(&createType, "type", "", "Issue 类型 (feature-request/bug-report/pack-register/pack-sync)")
+	createCmd.Flags().StringVar(&createType, "type", "", "Issue 类型 (feature-request/bug-report/pack-register/pack-sync/question/custom 或目标仓库注册的类型)")
 	createCmd.Flags().StringVar(&createTitle, "title", "", "Issue 标题")
-	createCmd.Flags().StringVar(&createPayload, "payload", "", "详细内容文件路径 (JSON)")
+	createCmd.Flags().StringVar(&createPayload, "payload", "", "详细内容文件路径 (JSON 或 .yaml/.yml)")
 	createCmd.Flags().StringSliceVar(&createAttach, "attach", nil, "附件文件路径")
 	createCmd.Flags().BoolVar(&createDryRun, "dry-run", false, "预览模式，不实际创建")
+	createCmd.Flags().BoolVar(&createNoMeta, "no-metadata", false, "不自动探测 git 仓库、系统和 CursorToolset 版本")
+	createCmd.Flags().StringArrayVar(&createMeta, "meta", nil, "覆盖来源信息 key=value，value 为空时不写入 (可多次使用)")
+	createCmd.Flags().StringVar(&createPackDir, "pack-dir", "", "从该目录的 package.json / version.json 读取包名和版本")
+	createCmd.Flags().BoolVar(&createNoDup, "no-duplicate-check", false, "不检查与打开 Issue 的重复")
+	createCmd.Flags().BoolVarP(&createWizard, "interactive", "i", false, "交互式引导填写，预览后确认再创建")
 
 	createCmd.MarkFlagRequired("repo")
-	createCmd.MarkFlagRequired("type")
-	createCmd.MarkFlagRequired("title")
+	// type 和 title 在非交互模式下由 create 操作校验
 }
 
 func runCreate(cmd *cobra.Command, args []string) error {
-	token := getToken(cmd)
-
-	// 验证 Issue 类型
-	issueType := models.IssueType(createType)
-	switch issueType {
-	case models.TypeFeatureRequest, models.TypeBugReport,
-		models.TypePackRegister, models.TypePackSync,
-		models.TypeQuestion, models.TypeCustom:
-		// valid
-	default:
-		return fmt.Errorf("无效的 Issue 类型: %s", createType)
+	// Issue 类型由 service 按内置类型和目标仓库的类型注册表校验
+	in := opInput{
+		"repo":    createRepo,
+		"type":    createType,
+		"title":   createTitle,
+		"dry_run": createDryRun,
+
+		"detect_metadata":      !createNoMeta,
+		"pack_dir":             createPackDir,
+		"skip_duplicate_check": createNoDup,
+	}
+
+	// 来源信息覆盖
+	if len(createMeta) > 0 {
+		overrides := map[string]interface{}{}
+		for _, kv := range createMeta {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok {
+				return fmt.Errorf("无效的 --meta: %s，格式为 key=value", kv)
+			}
+			overrides[key] = value
+		}
+		in["metadata"] = overrides
 	}
 
 	// 读取 payload
-	var payload interface{}
 	if createPayload != "" {
-		data, err := os.ReadFile(createPayload)
+		payload, err := readPayloadFile(createPayload)
 		if err != nil {
-			return fmt.Errorf("读取 payload 文件失败: %w", err)
-		}
-		if err := json.Unmarshal(data, &payload); err != nil {
-			return fmt.Errorf("解析 payload JSON 失败: %w", err)
-		}
-	} else {
-		// 使用默认 payload
-		payload = map[string]string{
-			"title":       createTitle,
-			"description": "",
+			return err
 		}
+		in["payload"] = payload
 	}
 
 	// 读取附件
@@ -89,26 +112,10 @@ func runCreate(cmd *cobra.Command, args []string) error {
 			Content: string(data),
 		})
 	}
+	in["attachments"] = attachments
 
-	// 创建 Issue
-	svc := service.NewIssueService(token)
-	result, err := svc.Create(service.CreateIssueOptions{
-		Repo:        createRepo,
-		Type:        issueType,
-		Title:       createTitle,
-		Payload:     payload,
-		Attachments: attachments,
-		DryRun:      createDryRun,
-	})
-	if err != nil {
-		return err
+	if createWizard {
+		return runCreateWizard(cmd, in)
 	}
-
-	if !createDryRun {
-		fmt.Println("✅ Issue 创建成功!")
-		fmt.Printf("   Issue: %s\n", result.IssueURL)
-		fmt.Printf("   Gist:  %s\n", result.GistURL)
-	}
-
-	return nil
+	return runOperation(cmd, "create", in, "text")
 }
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "扫描并合并重复的打开 Issue",
+	Long: `扫描仓库中打开的 Issue，将相似的同类型 Issue 分组。
+
+相似度按类型计算:
+  - pack-register: repository 相同为 1，包名相同为 0.8
+  - pack-sync: repository 和 version 都相同为 1
+  - 其他类型: 标题和描述的词语重合度
+
+每组以编号最小（最早创建）的 Issue 为规范 Issue。使用 --close 将其余 Issue
+以 duplicate 关闭，并在评论中以 "Duplicate of #N" 链接规范 Issue。
+无法读取 Issue 包的 Issue 不参与分组，也不会被关闭，在报告中单独列出。
+
+示例:
+  github-issue dedupe --repo owner/repo
+  github-issue dedupe --repo owner/repo --type pack-sync --close --dry-run
+  github-issue dedupe --repo owner/repo --threshold 0.8 --close`,
+	RunE: runDedupe,
+}
+
+var (
+	dedupeRepo      string
+	dedupeType      string
+	dedupeThreshold float64
+	dedupeClose     bool
+	dedupeComment   string
+	dedupeDryRun    bool
+	dedupeFormat    string
+)
+
+func init() {
+	rootCmd.AddCommand(dedupeCmd)
+
+	dedupeCmd.Flags().StringVar(&dedupeRepo, "repo", "", "目标仓库 (owner/repo)")
+	dedupeCmd.Flags().StringVar(&dedupeType, "type", "", "只扫描该类型的 Issue")
+	dedupeCmd.Flags().Float64Var(&dedupeThreshold, "threshold", service.DefaultDuplicateThreshold, "判定为重复的最低相似度 (0~1)")
+	dedupeCmd.Flags().BoolVar(&dedupeClose, "close", false, "以 duplicate 关闭重复 Issue")
+	dedupeCmd.Flags().StringVar(&dedupeComment, "comment", "", "关闭时附加的说明")
+	dedupeCmd.Flags().BoolVar(&dedupeDryRun, "dry-run", false, "预览模式，只输出分组不关闭")
+	formatFlag(dedupeCmd.Flags(), &dedupeFormat, "输出格式", "table", "json")
+
+	dedupeCmd.MarkFlagRequired("repo")
+}
+
+func runDedupe(cmd *cobra.Command, args []string) error {
+	if dedupeThreshold <= 0 || dedupeThreshold > 1 {
+		return fmt.Errorf("--threshold 必须在 0~1 之间: %g", dedupeThreshold)
+	}
+
+	svc := newIssueService(cmd)
+	report, err := svc.Dedupe(service.DedupeOptions{
+		Repo:      dedupeRepo,
+		Type:      dedupeType,
+		Threshold: dedupeThreshold,
+		Close:     dedupeClose,
+		Comment:   dedupeComment,
+		DryRun:    dedupeDryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if dedupeFormat == "json" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if dedupeDryRun {
+		fmt.Println("=== Dry Run 模式 ===")
+	}
+	printDedupeSkipped(report.Skipped)
+
+	groups := report.Groups
+	if len(groups) == 0 {
+		fmt.Println("没有发现重复的 Issue")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Canonical\tDuplicate\tScore\tTitle\tReason")
+	fmt.Fprintln(w, "---------\t---------\t-----\t-----\t------")
+	total, closed := 0, 0
+	for _, g := range groups {
+		fmt.Fprintf(w, "#%d\t\t\t%s\t[%s]\n", g.Canonical.Number, shortTitle(g.Canonical.Title), g.Type)
+		for _, d := range g.Duplicates {
+			reason := d.Reason
+			switch {
+			case d.Error != "":
+				reason += "；关闭失败: " + d.Error
+			case d.Closed:
+				reason += "；已关闭"
+				closed++
+			}
+			fmt.Fprintf(w, "\t#%d\t%.2f\t%s\t%s\n", d.Number, d.Score, shortTitle(d.Title), reason)
+			total++
+		}
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Printf("共 %d 组，%d 个重复 Issue", len(groups), total)
+	if dedupeClose && !dedupeDryRun {
+		fmt.Printf("，已关闭 %d 个", closed)
+	}
+	fmt.Println()
+	return nil
+}
+
+// printDedupeSkipped 列出读取 Issue 包失败、未参与分组的 Issue
+func printDedupeSkipped(skipped []service.DuplicateCandidate) {
+	if len(skipped) == 0 {
+		return
+	}
+	fmt.Printf("⚠️  %d 个 Issue 无法读取 Issue 包，未参与比较:\n", len(skipped))
+	for _, d := range skipped {
+		fmt.Printf("  #%d %s: %s\n", d.Number, shortTitle(d.Title), d.Error)
+	}
+	fmt.Println()
+}
+
+// shortTitle 截断过长的标题，按字符而非字节截断以免破坏中文
+func shortTitle(title string) string {
+	runes := []rune(title)
+	if len(runes) > 40 {
+		return string(runes[:37]) + "..."
+	}
+	return title
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+)
+
+// diffContext 差异中每处变更前后保留的上下文行数
+const diffContext = 3
+
+// revisionDiff 返回 Issue 包某个版本（默认最新）相对上一版本的 unified diff
+func revisionDiff(svc *service.IssueService, repo string, number int, rev string) (string, error) {
+	to, err := svc.GetRevision(repo, number, rev)
+	if err != nil {
+		return "", err
+	}
+	if to.Package == nil {
+		return "", fmt.Errorf("Issue #%d 没有可解析的 Issue 包", number)
+	}
+	prev, err := service.PreviousRevision(to.Revisions, to.Revision)
+	if err != nil {
+		return "", err
+	}
+	from, err := svc.GetRevision(repo, number, prev)
+	if err != nil {
+		return "", err
+	}
+
+	before, err := canonicalJSON(from.Package)
+	if err != nil {
+		return "", err
+	}
+	after, err := canonicalJSON(to.Package)
+	if err != nil {
+		return "", err
+	}
+	return unifiedDiff(before, after, "issue-payload.json@"+shortRevision(from.Revision), "issue-payload.json@"+shortRevision(to.Revision)), nil
+}
+
+// canonicalJSON 按字段名排序、不转义非 ASCII 字符输出 JSON，避免字段顺序和编码方式不同产生无意义的差异
+func canonicalJSON(v interface{}) (string, error) {
+	generic, err := jsonValue(v)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(generic); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func shortRevision(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// unifiedDiff 按行比较两段文本，输出 unified diff 格式，没有差异时返回空字符串
+func unifiedDiff(a, b, nameA, nameB string) string {
+	linesA := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+	linesB := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+	ops := diffLines(linesA, linesB)
+
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		// 找到下一处变更
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		// 向后扩展，两处变更之间的相同行不超过 2*diffContext 时合并为一个 hunk
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+		lo := max(start-diffContext, 0)
+		hi := min(end+diffContext, len(ops))
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+		}
+		var countA, countB int
+		for _, op := range ops[lo:hi] {
+			if op.kind != '+' {
+				countA++
+			}
+			if op.kind != '-' {
+				countB++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", ops[lo].lineA+1, countA, ops[lo].lineB+1, countB)
+		for _, op := range ops[lo:hi] {
+			fmt.Fprintf(&out, "%c%s\n", op.kind, op.text)
+		}
+		start = hi
+	}
+	return out.String()
+}
+
+// diffOp 一行的比较结果：' ' 相同、'-' 删除、'+' 新增；lineA/lineB 为该行之前两侧已输出的行数
+type diffOp struct {
+	kind         byte
+	text         string
+	lineA, lineB int
+}
+
+// diffLines 用最长公共子序列计算逐行差异，先去掉相同的首尾行以减少计算量
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] 为 midA[i:] 与 midB[j:] 的最长公共子序列长度
+	lcs := make([][]int32, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	la, lb := 0, 0
+	emit := func(kind byte, text string) {
+		ops = append(ops, diffOp{kind: kind, text: text, lineA: la, lineB: lb})
+		if kind != '+' {
+			la++
+		}
+		if kind != '-' {
+			lb++
+		}
+	}
+
+	for _, line := range a[:prefix] {
+		emit(' ', line)
+	}
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			emit(' ', midA[i])
+			i++
+			j++
+		case i < len(midA) && (j == len(midB) || lcs[i+1][j] >= lcs[i][j+1]):
+			emit('-', midA[i])
+			i++
+		default:
+			emit('+', midB[j])
+			j++
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		emit(' ', line)
+	}
+	return ops
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+)
+
+var discoverCmd = operationCommand("discover", "discover <owner/repo>", `读取目标仓库的 manifest (.github/issue-pack/manifest.json) 和类型注册表，
+显示其接受的类型、包格式版本、必需字段、可信发送方、加密公钥和处理时效。
+省略仓库时使用 profile 的 repo。
+
+manifest 示例:
+
+  {
+    "accepted_types": ["bug-report", "security-report"],
+    "schema_versions": ["cursortoolset-issue-v1"],
+    "required_fields": {"bug-report": ["title", "environment.os"]},
+    "trusted_senders": ["shichao402"],
+    "encryption_keys": [{"id": "2024", "type": "age", "public_key": "age1..."}],
+    "sla": {"first_response": "48h", "resolution": "14d"}
+  }
+
+示例:
+  github-issue discover owner/repo
+  github-issue discover owner/repo --format json`, "repo")
+
+func init() {
+	rootCmd.AddCommand(discoverCmd)
+}
+
+// discoverText 以文本显示仓库的接收能力（discover 命令和 MCP 工具共用）
+func discoverText(result *service.DiscoverResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "仓库: %s\n", result.Repo)
+	if !result.AcceptsPacks {
+		b.WriteString("⚠️ 该仓库未发布 manifest 或类型注册表，可能不处理 Issue 包\n")
+	}
+	fmt.Fprintf(&b, "可用类型: %s\n", strings.Join(result.Types, ", "))
+	if len(result.InvalidTypes) > 0 {
+		fmt.Fprintf(&b, "无效的类型定义: %s\n", strings.Join(result.InvalidTypes, ", "))
+	}
+
+	m := result.Manifest
+	if m == nil {
+		return b.String()
+	}
+	if m.Description != "" {
+		fmt.Fprintf(&b, "说明: %s\n", m.Description)
+	}
+	if len(m.SchemaVersions) > 0 {
+		fmt.Fprintf(&b, "包格式版本: %s\n", strings.Join(m.SchemaVersions, ", "))
+	}
+	requiredTypes := make([]string, 0, len(m.RequiredFields))
+	for t := range m.RequiredFields {
+		requiredTypes = append(requiredTypes, t)
+	}
+	sort.Strings(requiredTypes)
+	for _, t := range requiredTypes {
+		fmt.Fprintf(&b, "必需字段 [%s]: %s\n", t, strings.Join(m.RequiredFields[t], ", "))
+	}
+	if len(m.TrustedSenders) > 0 {
+		fmt.Fprintf(&b, "可信发送方: %s\n", strings.Join(m.TrustedSenders, ", "))
+	}
+	for _, key := range m.EncryptionKeys {
+		fmt.Fprintf(&b, "加密公钥: %s (%s) %s\n", key.ID, key.Type, key.PublicKey)
+	}
+	if m.SLA != nil {
+		if m.SLA.FirstResponse != "" {
+			fmt.Fprintf(&b, "首次响应时效: %s\n", m.SLA.FirstResponse)
+		}
+		if m.SLA.Resolution != "" {
+			fmt.Fprintf(&b, "处理完成时效: %s\n", m.SLA.Resolution)
+		}
+	}
+	if m.Contact != "" {
+		fmt.Fprintf(&b, "联系方式: %s\n", m.Contact)
+	}
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出仓库中的 Issue 和 Issue 包",
+	Long: `将仓库中的标准化 Issue、评论、标签和解包后的 Issue 包（含附件）导出到归档，
+用于备份或迁移。--output 以 .tar.gz/.tgz 结尾时写入压缩包，否则写入目录。
+
+归档结构:
+  manifest.json                         来源仓库、导出时间和标签定义
+  issues.jsonl                          每行一个 Issue（状态、类型、body、评论）
+  packages/<number>/issue-payload.json  Issue 包
+  packages/<number>/attachments/<name>  附件（名称清理为单个文件名，重名时追加序号）
+
+示例:
+  github-issue export --repo owner/repo --output ./inbox-backup
+  github-issue export --repo owner/repo --output inbox.tar.gz --status pending
+
+使用 "github-issue import" 将归档导入其他仓库。`,
+	RunE: runExport,
+}
+
+var (
+	exportRepo   string
+	exportOutput string
+	exportStatus string
+	exportType   string
+	exportFormat string
+)
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(&exportRepo, "repo", "", "目标仓库 (owner/repo)")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "归档路径（.tar.gz/.tgz 为压缩包，否则为目录）")
+	exportCmd.Flags().StringVar(&exportStatus, "status", "all", "状态过滤 (pending/processing/needs-info/processed/rejected/duplicate/all)")
+	exportCmd.Flags().StringVar(&exportType, "type", "", "只导出该类型的 Issue")
+	formatFlag(exportCmd.Flags(), &exportFormat, "输出格式", "text", "json")
+
+	exportCmd.MarkFlagRequired("repo")
+	exportCmd.MarkFlagRequired("output")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	svc := newIssueService(cmd)
+	report, err := svc.Export(service.ExportOptions{
+		Repo:   exportRepo,
+		Output: exportOutput,
+		Status: exportStatus,
+		Type:   exportType,
+	})
+	if err != nil {
+		return err
+	}
+
+	if exportFormat == "json" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("✅ 已导出到 %s\n", report.Output)
+	fmt.Printf("   Issue: %d，Issue 包: %d，附件: %d，评论: %d\n", report.Issues, report.Packages, report.Attachments, report.Comments)
+	if len(report.Missing) > 0 {
+		fmt.Printf("⚠️  %d 个 Issue 的 Issue 包无法读取，只导出了 Issue 和评论: %v\n", len(report.Missing), report.Missing)
+	}
+	return nil
+}
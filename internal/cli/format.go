@@ -0,0 +1,338 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// formatValuesAnnotation --format 参数上记录命令接受的格式的注解
+const formatValuesAnnotation = "github-issue/format-values"
+
+// formatFlag 注册 --format 参数，values 为命令接受的格式，第一个为默认值
+//
+// 接受的格式记录在参数注解中，profile 的 format 只在命令接受时作为默认值。
+func formatFlag(flags *pflag.FlagSet, p *string, usage string, values ...string) {
+	flags.StringVar(p, "format", values[0], fmt.Sprintf("%s (%s)", usage, strings.Join(values, "/")))
+	flags.SetAnnotation("format", formatValuesAnnotation, values)
+}
+
+// acceptsFormat 命令的 --format 参数是否接受该格式
+func acceptsFormat(flag *pflag.Flag, format string) bool {
+	return containsString(flag.Annotations[formatValuesAnnotation], format)
+}
+
+// readPayloadFile 读取 payload 文件，.yaml/.yml 按 YAML 解析，其余按 JSON 解析
+func readPayloadFile(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 payload 文件失败: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("解析 payload YAML 失败: %w", err)
+		}
+		// 经 JSON 转换，使数字等类型与 JSON payload 一致，便于 schema 校验
+		data, err = json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("解析 payload YAML 失败: %w", err)
+		}
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("解析 payload JSON 失败: %w", err)
+	}
+	return payload, nil
+}
+
+// toYAML 将 v 按其 JSON 形式输出为 YAML，字段名和顺序与 JSON 输出一致
+func toYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	blockStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	enc.Close()
+	return buf.Bytes(), nil
+}
+
+// blockStyle 清除从 JSON 解析得到的 flow 和引号样式，输出常规的块状 YAML
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+// jsonValue 返回 v 的 JSON 形式对应的通用值（map/slice/string/float64...）
+func jsonValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+// templateFuncs --template 中可用的函数
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		return string(data), err
+	},
+	"yaml": func(v interface{}) (string, error) {
+		data, err := toYAML(v)
+		return strings.TrimRight(string(data), "\n"), err
+	},
+	"join": func(v interface{}, sep string) string {
+		items, _ := v.([]interface{})
+		parts := make([]string, 0, len(items))
+		for _, item := range items {
+			parts = append(parts, fmt.Sprint(item))
+		}
+		return strings.Join(parts, sep)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// renderTemplate 用 Go 模板渲染 v，模板数据为 v 的 JSON 形式（字段名与 --format json 一致）
+func renderTemplate(text string, v interface{}) ([]byte, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败: %w", err)
+	}
+	data, err := jsonValue(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("渲染模板失败: %w", err)
+	}
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// renderGetMarkdown 将 Issue 及其包渲染为便于阅读的 Markdown 报告
+func renderGetMarkdown(result *service.GetResult) ([]byte, error) {
+	var b strings.Builder
+	issue := result.Issue
+	fmt.Fprintf(&b, "# #%d %s\n\n", issue.Number, issue.Title)
+	fmt.Fprintf(&b, "- 状态: %s\n", issue.State)
+	if pkg := result.Package; pkg != nil {
+		fmt.Fprintf(&b, "- 类型: %s\n", pkg.Type)
+	}
+	if len(issue.Labels) > 0 {
+		names := make([]string, 0, len(issue.Labels))
+		for _, l := range issue.Labels {
+			names = append(names, "`"+l.Name+"`")
+		}
+		fmt.Fprintf(&b, "- 标签: %s\n", strings.Join(names, " "))
+	}
+	fmt.Fprintf(&b, "- 创建时间: %s\n", issue.CreatedAt)
+	fmt.Fprintf(&b, "- 链接: %s\n", issue.HTMLURL)
+
+	pkg := result.Package
+	if pkg == nil {
+		b.WriteString("\n_该 Issue 没有可解析的 Issue 包_\n")
+		if result.Comments != nil {
+			writeMarkdownThread(&b, result.Comments)
+		}
+		return []byte(b.String()), nil
+	}
+
+	// 元数据
+	meta, err := orderedJSON(pkg.Meta)
+	if err != nil {
+		return nil, err
+	}
+	b.WriteString("\n## 元数据\n\n| 字段 | 值 |\n|------|----|\n")
+	for i := 0; i+1 < len(meta.Content); i += 2 {
+		fmt.Fprintf(&b, "| %s | %s |\n", meta.Content[i].Value, markdownCell(meta.Content[i+1].Value))
+	}
+	fmt.Fprintf(&b, "| schema | %s |\n", pkg.Schema)
+	if pkg.Target.Pack != "" {
+		fmt.Fprintf(&b, "| 目标包 | %s %s |\n", pkg.Target.Pack, pkg.Target.Version)
+	}
+	for i, r := range result.Revisions {
+		if len(result.Revisions) > 1 && r.Version == result.Revision {
+			fmt.Fprintf(&b, "| 版本 | %d/%d (`%s`) |\n", len(result.Revisions)-i, len(result.Revisions), shortRevision(r.Version))
+		}
+	}
+
+	// payload 按原始字段顺序输出
+	b.WriteString("\n## 内容\n")
+	if len(pkg.Payload) > 0 {
+		var node yaml.Node
+		if err := yaml.Unmarshal(pkg.Payload, &node); err != nil {
+			return nil, fmt.Errorf("解析 payload 失败: %w", err)
+		}
+		if len(node.Content) > 0 {
+			writeMarkdownSections(&b, node.Content[0])
+		}
+	}
+
+	if len(pkg.Attachments) > 0 {
+		b.WriteString("\n## 附件\n")
+		for _, att := range pkg.Attachments {
+			fence := "```"
+			for strings.Contains(att.Content, fence) {
+				fence += "`"
+			}
+			lang := strings.TrimPrefix(filepath.Ext(att.Name), ".")
+			fmt.Fprintf(&b, "\n### %s\n\n%s%s\n%s\n%s\n", att.Name, fence, lang, strings.TrimRight(att.Content, "\n"), fence)
+		}
+	}
+	if result.Comments != nil {
+		writeMarkdownThread(&b, result.Comments)
+	}
+	return []byte(b.String()), nil
+}
+
+// formatThread 将评论会话渲染为文本，状态评论单独标出
+func formatThread(comments []service.ThreadComment) string {
+	var b strings.Builder
+	for _, c := range comments {
+		if c.IsStatus() {
+			fmt.Fprintf(&b, "\n--- [状态 → %s] @%s %s ---\n%s\n", c.Status, c.Author, c.CreatedAt, c.Body)
+		} else if c.Reminder {
+			fmt.Fprintf(&b, "\n--- [提醒] @%s %s ---\n%s\n", c.Author, c.CreatedAt, c.Body)
+		} else {
+			fmt.Fprintf(&b, "\n--- @%s %s ---\n%s\n", c.Author, c.CreatedAt, c.Body)
+		}
+	}
+	return b.String()
+}
+
+// writeMarkdownThread 输出评论会话，工具生成的评论渲染为引用块
+func writeMarkdownThread(b *strings.Builder, comments []service.ThreadComment) {
+	fmt.Fprintf(b, "\n## 讨论 (%d)\n", len(comments))
+	if len(comments) == 0 {
+		b.WriteString("\n_暂无评论_\n")
+	}
+	for _, c := range comments {
+		if c.IsGenerated() {
+			label := "提醒"
+			if c.IsStatus() {
+				label = "状态 → " + c.Status
+			}
+			fmt.Fprintf(b, "\n> **%s** · @%s · %s\n", label, c.Author, c.CreatedAt)
+			if body := strings.TrimSpace(c.Body); body != "" {
+				for _, line := range strings.Split(body, "\n") {
+					fmt.Fprintf(b, "> %s\n", line)
+				}
+			}
+			continue
+		}
+		fmt.Fprintf(b, "\n### @%s · %s\n\n%s\n", c.Author, c.CreatedAt, strings.TrimSpace(c.Body))
+	}
+}
+
+// orderedJSON 将 v 的 JSON 形式解析为保持字段顺序的 YAML 节点
+func orderedJSON(v interface{}) (*yaml.Node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	return node.Content[0], nil
+}
+
+// writeMarkdownSections 将 payload 的顶层字段输出为小节：字符串为段落，数组为编号列表，对象为列表
+func writeMarkdownSections(b *strings.Builder, n *yaml.Node) {
+	if n.Kind != yaml.MappingNode {
+		fmt.Fprintf(b, "\n%s\n", n.Value)
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i].Value, n.Content[i+1]
+		// 标题已作为报告标题输出
+		if key == "title" || isEmptyNode(value) {
+			continue
+		}
+		fmt.Fprintf(b, "\n### %s\n\n", fieldLabel(key))
+		switch value.Kind {
+		case yaml.SequenceNode:
+			for j, item := range value.Content {
+				fmt.Fprintf(b, "%d. %s\n", j+1, inlineNode(item))
+			}
+		case yaml.MappingNode:
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				if isEmptyNode(value.Content[j+1]) {
+					continue
+				}
+				fmt.Fprintf(b, "- %s: %s\n", fieldLabel(value.Content[j].Value), inlineNode(value.Content[j+1]))
+			}
+		default:
+			fmt.Fprintf(b, "%s\n", value.Value)
+		}
+	}
+}
+
+// inlineNode 将节点渲染为单行文本，嵌套结构输出为 JSON
+func inlineNode(n *yaml.Node) string {
+	if n.Kind == yaml.ScalarNode {
+		return n.Value
+	}
+	var v interface{}
+	n.Decode(&v)
+	data, _ := json.Marshal(v)
+	return "`" + string(data) + "`"
+}
+
+func isEmptyNode(n *yaml.Node) bool {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return n.Value == "" || n.Tag == "!!null"
+	case yaml.SequenceNode, yaml.MappingNode:
+		return len(n.Content) == 0
+	}
+	return false
+}
+
+// markdownCell 转义表格单元格中的竖线和换行
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// renderListMarkdown 将 Issue 列表渲染为 Markdown 表格
+func renderListMarkdown(issues []service.IssueInfo) []byte {
+	var b strings.Builder
+	b.WriteString("| # | 类型 | 状态 | 标题 | 创建时间 |\n|---|------|------|------|----------|\n")
+	for _, issue := range issues {
+		fmt.Fprintf(&b, "| [#%d](%s) | %s | %s | %s | %s |\n",
+			issue.Number, issue.URL, issue.Type, issue.Status, markdownCell(issue.Title), issue.CreatedAt)
+	}
+	return []byte(b.String())
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+type formatItem struct {
+	Number int      `json:"number"`
+	Title  string   `json:"title"`
+	Labels []string `json:"labels,omitempty"`
+	Body   string   `json:"body,omitempty"`
+}
+
+func TestToYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		want string
+	}{
+		{
+			name: "按 JSON 字段名和顺序输出",
+			in:   formatItem{Number: 1, Title: "登录失败", Labels: []string{"bug-report", "pending"}},
+			want: "number: 1\ntitle: 登录失败\nlabels:\n  - bug-report\n  - pending\n",
+		},
+		{
+			name: "省略空字段",
+			in:   formatItem{Number: 2, Title: "x"},
+			want: "number: 2\ntitle: x\n",
+		},
+		{
+			name: "多行文本",
+			in:   formatItem{Number: 3, Title: "x", Body: "a\nb"},
+			want: "number: 3\ntitle: x\nbody: |-\n  a\n  b\n",
+		},
+		{
+			name: "数组",
+			in:   []formatItem{{Number: 1, Title: "a"}, {Number: 2, Title: "b"}},
+			want: "- number: 1\n  title: a\n- number: 2\n  title: b\n",
+		},
+		{
+			name: "空数组",
+			in:   []formatItem{},
+			want: "[]\n",
+		},
+	}
+	for _, tt := range tests {
+		got, err := toYAML(tt.in)
+		if err != nil {
+			t.Errorf("%s: toYAML 报错: %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: toYAML =\n%s\n期望\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	items := []formatItem{
+		{Number: 1, Title: "a", Labels: []string{"bug-report", "pending"}},
+		{Number: 2, Title: "b"},
+	}
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		in      interface{}
+		want    string
+		wantErr string
+	}{
+		{
+			name: "使用 JSON 字段名",
+			tmpl: "#{{.number}} {{.title}}",
+			in:   items[0],
+			want: "#1 a\n",
+		},
+		{
+			name: "遍历数组",
+			tmpl: "{{range .}}{{.number}}:{{join .labels \",\"}}\n{{end}}",
+			in:   items,
+			want: "1:bug-report,pending\n2:\n",
+		},
+		{
+			name: "缺少的字段为空值",
+			tmpl: "{{if .missing}}x{{else}}-{{end}}|{{upper .title}}",
+			in:   items[1],
+			want: "-|B\n",
+		},
+		{
+			name: "json 和 yaml 函数",
+			tmpl: "{{json .labels}}\n{{yaml .labels}}",
+			in:   items[0],
+			want: "[\n  \"bug-report\",\n  \"pending\"\n]\n- bug-report\n- pending\n",
+		},
+		{
+			name: "空输出不追加换行",
+			tmpl: "{{if .body}}{{.body}}{{end}}",
+			in:   items[0],
+			want: "",
+		},
+		{
+			name:    "模板语法错误",
+			tmpl:    "{{.title",
+			in:      items[0],
+			wantErr: "解析模板失败",
+		},
+		{
+			name:    "执行错误",
+			tmpl:    "{{index .labels 5}}",
+			in:      items[0],
+			wantErr: "渲染模板失败",
+		},
+	}
+	for _, tt := range tests {
+		got, err := renderTemplate(tt.tmpl, tt.in)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: renderTemplate 错误 = %v, 期望包含 %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: renderTemplate 报错: %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: renderTemplate = %q, 期望 %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "清理孤立和过期的 payload Gist",
+	Long: `清理本工具创建的 payload Gist。
+
+以下 Gist 会被清理:
+  - 孤立 Gist: 没有任何 Issue 引用（通常是 Issue 创建失败遗留的）
+  - 过期 Gist: 关联 Issue 已关闭超过保留期
+
+示例:
+  github-issue gc --dry-run
+  github-issue gc --repo owner/repo --retention 30d
+  github-issue gc --retention 90d --archive ./gist-archive`,
+	RunE: runGC,
+}
+
+var (
+	gcRepo      string
+	gcRetention string
+	gcArchive   string
+	gcDryRun    bool
+	gcFormat    string
+)
+
+func init() {
+	rootCmd.AddCommand(gcCmd)
+
+	gcCmd.Flags().StringVar(&gcRepo, "repo", "", "仅处理目标为该仓库的 Gist (owner/repo)")
+	gcCmd.Flags().StringVar(&gcRetention, "retention", "30d", "Issue 关闭后的保留期 (如 30d, 72h)")
+	gcCmd.Flags().StringVar(&gcArchive, "archive", "", "删除前将 Gist 文件归档到该目录")
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "预览模式，只输出报告不实际删除")
+	formatFlag(gcCmd.Flags(), &gcFormat, "输出格式", "table", "json")
+}
+
+func runGC(cmd *cobra.Command, args []string) error {
+	retention, err := service.ParseDuration(gcRetention)
+	if err != nil {
+		return err
+	}
+
+	svc := newIssueService(cmd)
+	report, err := svc.GC(service.GCOptions{
+		Repo:       gcRepo,
+		Retention:  retention,
+		ArchiveDir: gcArchive,
+		DryRun:     gcDryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if gcFormat == "json" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if report.DryRun {
+		fmt.Println("=== Dry Run 模式 ===")
+	}
+
+	if len(report.Items) == 0 {
+		fmt.Println("没有找到本工具创建的 Gist")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Gist\tRepo\tIssue\tAction\tReason")
+	fmt.Fprintln(w, "----\t----\t-----\t------\t------")
+	for _, item := range report.Items {
+		issue := "-"
+		if item.IssueNum > 0 {
+			issue = fmt.Sprintf("#%d (%s)", item.IssueNum, item.IssueState)
+		}
+		reason := item.Reason
+		if item.Error != "" {
+			reason += ": " + item.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			item.GistID, item.TargetRepo, issue, item.Action, reason)
+	}
+	w.Flush()
+
+	fmt.Println()
+	if report.DryRun {
+		pending := 0
+		for _, item := range report.Items {
+			if item.Action == service.GCDeleteOrphan || item.Action == service.GCDeleteStale {
+				pending++
+			}
+		}
+		fmt.Printf("共扫描 %d 个 Gist，将删除 %d 个\n", report.Scanned, pending)
+	} else {
+		fmt.Printf("共扫描 %d 个 Gist，已删除 %d 个，已归档 %d 个\n", report.Scanned, report.Deleted, report.Archived)
+	}
+
+	return nil
+}
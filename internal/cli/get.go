@@ -1,59 +1,43 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"strconv"
+	"strings"
 
 	"github.com/shichao402/github-issue-pack/internal/service"
-	"github.com/spf13/cobra"
 )
 
-var getCmd = &cobra.Command{
-	Use:   "get <issue-number>",
-	Short: "获取并解析指定 Issue",
-	Long: `获取指定 Issue 并解析其中的结构化数据。
+var getCmd = operationCommandWith("get", "get <issue-number>", `获取指定 Issue 并解析其中的结构化数据。
 
 示例:
   github-issue get 123 --repo owner/repo
   github-issue get 123 --repo owner/repo --format json
-  github-issue get 123 --repo owner/repo --output issue.json`,
-	Args: cobra.ExactArgs(1),
-	RunE: runGet,
-}
-
-var (
-	getRepo   string
-	getFormat string
-	getOutput string
-)
+  github-issue get 123 --repo owner/repo --format markdown
+  github-issue get 123 --repo owner/repo --comments --format text
+  github-issue get 123 --repo owner/repo --template '{{.issue.title}}: {{.package.payload.description}}'
+  github-issue get 123 --repo owner/repo --output issue.json
+
+Issue 包被 amend 修改后，旧版本保留在 Gist 历史中:
+  github-issue get 123 --repo owner/repo --revision 1        # 最初版本
+  github-issue get 123 --repo owner/repo --revision a1b2c3d  # 按版本 SHA
+  github-issue get 123 --repo owner/repo --diff --template '{{.diff}}'  # 最新版本相对上一版本的变更`, cliOptions{
+	Formats: []string{"json", "yaml", "markdown", "text"},
+	Renderers: map[string]opRenderer{
+		"markdown": func(result *opResult) ([]byte, error) {
+			return renderGetMarkdown(result.Value.(*service.GetResult))
+		},
+	},
+	Defaults: map[string]interface{}{"comments": false},
+	Template: true,
+	Output:   true,
+}, "number")
 
 func init() {
 	rootCmd.AddCommand(getCmd)
-
-	getCmd.Flags().StringVar(&getRepo, "repo", "", "目标仓库 (owner/repo)")
-	getCmd.Flags().StringVar(&getFormat, "format", "json", "输出格式 (json/text)")
-	getCmd.Flags().StringVar(&getOutput, "output", "", "输出到文件")
-
-	getCmd.MarkFlagRequired("repo")
 }
 
-func runGet(cmd *cobra.Command, args []string) error {
-	token := getToken(cmd)
-
-	number, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("无效的 Issue 编号: %s", args[0])
-	}
-
-	svc := service.NewIssueService(token)
-	result, err := svc.Get(getRepo, number)
-	if err != nil {
-		return err
-	}
-
-	// 构建输出
+// buildGetOutput 构建 Issue 及其包的结构化输出（get 命令和 MCP 资源共用）
+func buildGetOutput(result *service.GetResult) map[string]interface{} {
 	output := map[string]interface{}{
 		"issue": map[string]interface{}{
 			"number":     result.Issue.Number,
@@ -68,33 +52,37 @@ func runGet(cmd *cobra.Command, args []string) error {
 	if result.Package != nil {
 		output["package"] = result.Package
 	}
-
-	var outputData []byte
-	if getFormat == "text" {
-		fmt.Printf("Issue #%d: %s\n", result.Issue.Number, result.Issue.Title)
-		fmt.Printf("State: %s\n", result.Issue.State)
-		fmt.Printf("URL: %s\n", result.Issue.HTMLURL)
-		fmt.Printf("Created: %s\n", result.Issue.CreatedAt)
-		if result.Package != nil {
-			fmt.Printf("\n--- Package ---\n")
-			fmt.Printf("Type: %s\n", result.Package.Type)
-			fmt.Printf("Schema: %s\n", result.Package.Schema)
-			pkgData, _ := json.MarshalIndent(result.Package, "", "  ")
-			fmt.Println(string(pkgData))
+	if result.Revision != "" {
+		output["revision"] = result.Revision
+		revisions := make([]map[string]interface{}, 0, len(result.Revisions))
+		for i, r := range result.Revisions {
+			revisions = append(revisions, map[string]interface{}{
+				"number":       len(result.Revisions) - i,
+				"version":      r.Version,
+				"committed_at": r.CommittedAt,
+			})
 		}
-		return nil
+		output["revisions"] = revisions
+	}
+	if result.Comments != nil {
+		output["comments"] = result.Comments
 	}
 
-	outputData, _ = json.MarshalIndent(output, "", "  ")
+	return output
+}
 
-	if getOutput != "" {
-		if err := os.WriteFile(getOutput, outputData, 0644); err != nil {
-			return fmt.Errorf("写入文件失败: %w", err)
+// formatRevisions 输出当前版本的序号（如 "2/3 (a1b2c3d)"）和按序号列出的版本历史
+func formatRevisions(result *service.GetResult) string {
+	var b strings.Builder
+	total := len(result.Revisions)
+	for i, r := range result.Revisions {
+		if r.Version == result.Revision {
+			fmt.Fprintf(&b, "%d/%d (%s)", total-i, total, shortRevision(r.Version))
 		}
-		fmt.Printf("已保存到 %s\n", getOutput)
-	} else {
-		fmt.Println(string(outputData))
 	}
-
-	return nil
+	b.WriteString("\nHistory:")
+	for i, r := range result.Revisions {
+		fmt.Fprintf(&b, "\n  %d  %s  %s", total-i, shortRevision(r.Version), r.CommittedAt)
+	}
+	return b.String()
 }
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <archive>",
+	Short: "将导出的归档导入仓库",
+	Long: `将 "github-issue export" 生成的归档（目录或 .tar.gz）导入目标仓库。
+
+按原编号顺序为每个 Issue 重新上传 Issue 包、创建 Issue 并依次发表原评论（注明原作者和时间），
+保留类型和状态，已关闭的 Issue 导入后关闭。新 Issue 的 body 链接原 Issue；
+目标仓库中已有从同一 Issue 导入的 Issue 时跳过，上次中断时缺少的评论和关闭会被补齐，
+因此中断后可重新执行。
+缺失的状态和类型标签会自动创建。
+
+示例:
+  github-issue import inbox.tar.gz --repo new-owner/inbox --dry-run
+  github-issue import ./inbox-backup --repo new-owner/inbox`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+var (
+	importRepo   string
+	importDryRun bool
+	importFormat string
+)
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVar(&importRepo, "repo", "", "导入的目标仓库 (owner/repo)")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "预览模式，只列出将导入的 Issue")
+	formatFlag(importCmd.Flags(), &importFormat, "输出格式", "table", "json")
+
+	importCmd.MarkFlagRequired("repo")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	svc := newIssueService(cmd)
+	report, err := svc.Import(service.ImportOptions{
+		Archive: args[0],
+		Repo:    importRepo,
+		DryRun:  importDryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	counts := map[service.ImportAction]int{}
+	for _, item := range report.Items {
+		counts[item.Action]++
+	}
+
+	if importFormat == "json" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		if report.DryRun {
+			fmt.Println("=== Dry Run 模式 ===")
+		}
+		fmt.Printf("%s → %s\n\n", report.Source, report.Repo)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Original\tNew\tStatus\tComments\tAction\tTitle")
+		fmt.Fprintln(w, "--------\t---\t------\t--------\t------\t-----")
+		for _, item := range report.Items {
+			number := "-"
+			if item.Number > 0 {
+				number = fmt.Sprintf("#%d", item.Number)
+			}
+			action := string(item.Action)
+			if item.Error != "" {
+				action += ": " + item.Error
+			}
+			fmt.Fprintf(w, "#%d\t%s\t%s\t%d\t%s\t%s\n", item.Original, number, item.Status, item.Comments, action, shortTitle(item.Title))
+		}
+		w.Flush()
+
+		fmt.Println()
+		fmt.Printf("共 %d 个 Issue：导入 %d 个，补齐 %d 个，跳过 %d 个，失败 %d 个\n", len(report.Items),
+			counts[service.ImportCreated], counts[service.ImportResumed], counts[service.ImportSkipped], counts[service.ImportFailed])
+	}
+
+	if counts[service.ImportFailed] > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d 个 Issue 导入失败，重新执行将跳过已导入的 Issue 并补齐未完成的导入", counts[service.ImportFailed])
+	}
+	return nil
+}
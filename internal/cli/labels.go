@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var labelsCmd = &cobra.Command{
+	Use:   "labels",
+	Short: "查看和同步标签体系",
+	Long: `查看当前仓库生效的标签体系，或在仓库中创建缺失的标签。
+
+标签名可以在配置文件的 profile 或 repos 中自定义，例如:
+
+  profiles:
+    default:
+      labels:
+        namespace: gip        # 生成 gip、gip:status/pending、gip:type/bug-report
+  repos:
+    owner/repo:
+      labels:
+        types:
+          bug-report: kind/bug
+
+示例:
+  github-issue labels show --repo owner/repo
+  github-issue labels sync --repo owner/repo --dry-run`,
+}
+
+var labelsShowCmd = operationCommandWith("labels", "show", "", cliOptions{
+	Formats:   []string{"table", "json", "text"},
+	Renderers: map[string]opRenderer{"table": renderLabelsTable},
+})
+
+var labelsSyncCmd = operationCommand("labels_sync", "sync", "")
+
+func init() {
+	rootCmd.AddCommand(labelsCmd)
+	labelsCmd.AddCommand(labelsShowCmd)
+	labelsCmd.AddCommand(labelsSyncCmd)
+}
+
+// renderLabelsTable 以表格显示标签映射
+func renderLabelsTable(result *opResult) ([]byte, error) {
+	specs, _ := result.Value.([]service.LabelSpec)
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Label\tColor\tDescription")
+	fmt.Fprintln(w, "-----\t-----\t-----------")
+	for _, spec := range specs {
+		fmt.Fprintf(w, "%s\t#%s\t%s\n", spec.Name, spec.Color, spec.Description)
+	}
+	w.Flush()
+	return buf.Bytes(), nil
+}
@@ -1,74 +1,56 @@
 package cli
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
-	"os"
 	"text/tabwriter"
 
 	"github.com/shichao402/github-issue-pack/internal/service"
-	"github.com/spf13/cobra"
 )
 
-var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "列出待处理的 Issue",
-	Long: `列出带有 cursortoolset 标签的 Issue。
+var listCmd = operationCommandWith("list", "list", `列出带有 cursortoolset 标签的 Issue。
+
+--format json 输出 Issue 数组（即 MCP 结果中的 issues），--template 的数据与之一致。
 
 示例:
   github-issue list --repo owner/repo
   github-issue list --repo owner/repo --status pending
-  github-issue list --repo owner/repo --type feature-request --format json`,
-	RunE: runList,
-}
-
-var (
-	listRepo   string
-	listStatus string
-	listType   string
-	listLimit  int
-	listFormat string
-)
+  github-issue list --repo owner/repo --type feature-request --format json
+  github-issue list --repo owner/repo --format markdown
+  github-issue list --repo owner/repo --template '{{range .}}{{println .number .title}}{{end}}'`, cliOptions{
+	Formats: []string{"table", "json", "yaml", "markdown", "text"},
+	Renderers: map[string]opRenderer{
+		"table": renderListTable,
+		"markdown": func(result *opResult) ([]byte, error) {
+			return renderListMarkdown(listIssues(result)), nil
+		},
+	},
+	Data: func(result *opResult) interface{} {
+		return listIssues(result)
+	},
+	Defaults: map[string]interface{}{"status": "pending"},
+	Template: true,
+})
 
 func init() {
 	rootCmd.AddCommand(listCmd)
-
-	listCmd.Flags().StringVar(&listRepo, "repo", "", "目标仓库 (owner/repo)")
-	listCmd.Flags().StringVar(&listStatus, "status", "pending", "状态过滤 (pending/processing/processed/all)")
-	listCmd.Flags().StringVar(&listType, "type", "", "类型过滤")
-	listCmd.Flags().IntVar(&listLimit, "limit", 20, "数量限制")
-	listCmd.Flags().StringVar(&listFormat, "format", "table", "输出格式 (table/json)")
-
-	listCmd.MarkFlagRequired("repo")
 }
 
-func runList(cmd *cobra.Command, args []string) error {
-	token := getToken(cmd)
-
-	svc := service.NewIssueService(token)
-	issues, err := svc.List(service.ListOptions{
-		Repo:   listRepo,
-		Status: listStatus,
-		Type:   listType,
-		Limit:  listLimit,
-	})
-	if err != nil {
-		return err
-	}
+// listIssues 取出 list 操作结果中的 Issue
+func listIssues(result *opResult) []service.IssueInfo {
+	issues, _ := result.Value.([]service.IssueInfo)
+	return issues
+}
 
+// renderListTable 以表格显示 Issue 列表
+func renderListTable(result *opResult) ([]byte, error) {
+	issues := listIssues(result)
 	if len(issues) == 0 {
-		fmt.Println("没有找到符合条件的 Issue")
-		return nil
+		return []byte(result.Text + "\n"), nil
 	}
 
-	if listFormat == "json" {
-		data, _ := json.MarshalIndent(issues, "", "  ")
-		fmt.Println(string(data))
-		return nil
-	}
-
-	// 表格输出
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
 	fmt.Fprintln(w, "#\tType\tStatus\tTitle\tCreated")
 	fmt.Fprintln(w, "---\t----\t------\t-----\t-------")
 	for _, issue := range issues {
@@ -80,6 +62,5 @@ func runList(cmd *cobra.Command, args []string) error {
 			issue.Number, issue.Type, issue.Status, title, issue.CreatedAt)
 	}
 	w.Flush()
-
-	return nil
+	return buf.Bytes(), nil
 }
@@ -0,0 +1,317 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+	"github.com/shichao402/github-issue-pack/internal/service"
+)
+
+// metricsEndpoint Prometheus 指标端点路径
+const metricsEndpoint = "/metrics"
+
+// metricsDurationBuckets GitHub API 调用耗时直方图的桶（秒）
+var metricsDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// githubRequestKey GitHub API 调用计数的标签
+type githubRequestKey struct {
+	endpoint, method, status string
+}
+
+// githubDurationKey GitHub API 调用耗时的标签
+type githubDurationKey struct {
+	endpoint, method string
+}
+
+// durationHistogram 累积直方图，counts[i] 为耗时不超过 metricsDurationBuckets[i] 的次数
+type durationHistogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (h *durationHistogram) observe(seconds float64) {
+	for i, bound := range metricsDurationBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// inboxKey 收件箱深度的标签
+type inboxKey struct {
+	repo, status string
+}
+
+// metricsRegistry serve/watch 长期运行时的指标，以 Prometheus 文本格式输出
+//
+// 未启用 --metrics-listen 时为 nil，所有方法对 nil 不做任何事。
+type metricsRegistry struct {
+	mu sync.Mutex
+
+	started            time.Time
+	githubRequests     map[githubRequestKey]uint64
+	githubDurations    map[githubDurationKey]*durationHistogram
+	rateLimitRemaining float64
+	rateLimitKnown     bool
+	toolCalls          map[string]uint64
+	toolErrors         map[string]uint64
+	inbox              map[inboxKey]int
+	inboxRefreshed     map[string]time.Time
+	inboxErrors        map[string]uint64
+}
+
+// activeMetrics 当前进程的指标，未指定 --metrics-listen 时为 nil
+var activeMetrics *metricsRegistry
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{
+		started:         time.Now(),
+		githubRequests:  make(map[githubRequestKey]uint64),
+		githubDurations: make(map[githubDurationKey]*durationHistogram),
+		toolCalls:       make(map[string]uint64),
+		toolErrors:      make(map[string]uint64),
+		inbox:           make(map[inboxKey]int),
+		inboxRefreshed:  make(map[string]time.Time),
+		inboxErrors:     make(map[string]uint64),
+	}
+}
+
+// startMetricsServer 在 listen 上提供 /metrics 并设置 activeMetrics，监听失败时返回错误
+func startMetricsServer(listen string) error {
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("启动指标端点失败: %w", err)
+	}
+	activeMetrics = newMetricsRegistry()
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsEndpoint, activeMetrics)
+	go http.Serve(ln, mux)
+
+	fmt.Fprintf(os.Stderr, "指标端点已启动: http://%s%s\n", ln.Addr(), metricsEndpoint)
+	return nil
+}
+
+// observeGitHub 记录一次 GitHub API 调用，可直接作为 WithTracer 的回调
+func (m *metricsRegistry) observeGitHub(t github.RequestTrace) {
+	if m == nil {
+		return
+	}
+	status := "error" // 请求未发出或未收到响应
+	if t.Status != 0 {
+		status = strconv.Itoa(t.Status)
+	}
+	endpoint := t.Endpoint()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.githubRequests[githubRequestKey{endpoint, t.Method, status}]++
+	h := m.githubDurations[githubDurationKey{endpoint, t.Method}]
+	if h == nil {
+		h = &durationHistogram{counts: make([]uint64, len(metricsDurationBuckets))}
+		m.githubDurations[githubDurationKey{endpoint, t.Method}] = h
+	}
+	h.observe(t.Duration.Seconds())
+	if remaining, err := strconv.ParseFloat(t.RateLimitRemaining, 64); err == nil {
+		m.rateLimitRemaining, m.rateLimitKnown = remaining, true
+	}
+}
+
+// observeToolCall 记录一次 MCP 工具调用
+func (m *metricsRegistry) observeToolCall(tool string, failed bool) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.toolCalls[tool]++
+	if failed {
+		m.toolErrors[tool]++
+	}
+}
+
+// setInbox 更新仓库各收件箱状态下的 Issue 数量
+func (m *metricsRegistry) setInbox(repo string, depth map[string]int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for status, n := range depth {
+		m.inbox[inboxKey{repo, status}] = n
+	}
+	m.inboxRefreshed[repo] = time.Now()
+}
+
+// inboxFailed 记录一次收件箱统计失败
+func (m *metricsRegistry) inboxFailed(repo string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inboxErrors[repo]++
+}
+
+// refreshInbox 立即并按 interval 定期统计各仓库的收件箱深度，直到 ctx 结束
+func (m *metricsRegistry) refreshInbox(ctx context.Context, svc *service.IssueService, repos []string, interval time.Duration) {
+	if m == nil || len(repos) == 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, repo := range repos {
+			depth, err := svc.InboxDepth(repo)
+			if err != nil {
+				m.inboxFailed(repo)
+				serveLog.log("warning", "metrics", "统计收件箱失败", logFields{"repo": repo, "error": err.Error()})
+				continue
+			}
+			m.setInbox(repo, depth)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *metricsRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.write(w)
+}
+
+// write 以 Prometheus 文本格式输出全部指标，同一指标的样本按标签排序以保证输出稳定
+func (m *metricsRegistry) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writeMetricHeader(w, "github_issue_info", "gauge", "构建信息")
+	fmt.Fprintf(w, "github_issue_info{version=%s} 1\n", quoteLabel(Version))
+	writeMetricHeader(w, "github_issue_start_time_seconds", "gauge", "进程启动时间 (Unix 秒)")
+	fmt.Fprintf(w, "github_issue_start_time_seconds %d\n", m.started.Unix())
+
+	writeMetricHeader(w, "github_issue_github_requests_total", "counter", "GitHub API 调用次数，status 为 HTTP 状态码，未收到响应时为 error")
+	requests := make([]githubRequestKey, 0, len(m.githubRequests))
+	for k := range m.githubRequests {
+		requests = append(requests, k)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		a, b := requests[i], requests[j]
+		if a.endpoint != b.endpoint {
+			return a.endpoint < b.endpoint
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		return a.status < b.status
+	})
+	for _, k := range requests {
+		fmt.Fprintf(w, "github_issue_github_requests_total{endpoint=%s,method=%s,status=%s} %d\n",
+			quoteLabel(k.endpoint), quoteLabel(k.method), quoteLabel(k.status), m.githubRequests[k])
+	}
+
+	writeMetricHeader(w, "github_issue_github_request_duration_seconds", "histogram", "GitHub API 调用耗时")
+	durations := make([]githubDurationKey, 0, len(m.githubDurations))
+	for k := range m.githubDurations {
+		durations = append(durations, k)
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		a, b := durations[i], durations[j]
+		if a.endpoint != b.endpoint {
+			return a.endpoint < b.endpoint
+		}
+		return a.method < b.method
+	})
+	for _, k := range durations {
+		h := m.githubDurations[k]
+		labels := fmt.Sprintf("endpoint=%s,method=%s", quoteLabel(k.endpoint), quoteLabel(k.method))
+		for i, bound := range metricsDurationBuckets {
+			fmt.Fprintf(w, "github_issue_github_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n",
+				labels, strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i])
+		}
+		fmt.Fprintf(w, "github_issue_github_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		fmt.Fprintf(w, "github_issue_github_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "github_issue_github_request_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+
+	if m.rateLimitKnown {
+		writeMetricHeader(w, "github_issue_github_ratelimit_remaining", "gauge", "最近一次 GitHub API 响应中的剩余请求配额")
+		fmt.Fprintf(w, "github_issue_github_ratelimit_remaining %s\n", strconv.FormatFloat(m.rateLimitRemaining, 'g', -1, 64))
+	}
+
+	writeMetricHeader(w, "github_issue_tool_calls_total", "counter", "MCP 工具调用次数")
+	writeCounterByLabel(w, "github_issue_tool_calls_total", "tool", m.toolCalls)
+	writeMetricHeader(w, "github_issue_tool_errors_total", "counter", "MCP 工具调用失败次数")
+	writeCounterByLabel(w, "github_issue_tool_errors_total", "tool", m.toolErrors)
+
+	writeMetricHeader(w, "github_issue_inbox_issues", "gauge", "仓库中打开的 Issue 在各状态下的数量")
+	inbox := make([]inboxKey, 0, len(m.inbox))
+	for k := range m.inbox {
+		inbox = append(inbox, k)
+	}
+	sort.Slice(inbox, func(i, j int) bool {
+		if inbox[i].repo != inbox[j].repo {
+			return inbox[i].repo < inbox[j].repo
+		}
+		return inbox[i].status < inbox[j].status
+	})
+	for _, k := range inbox {
+		fmt.Fprintf(w, "github_issue_inbox_issues{repo=%s,status=%s} %d\n", quoteLabel(k.repo), quoteLabel(k.status), m.inbox[k])
+	}
+
+	writeMetricHeader(w, "github_issue_inbox_last_refresh_timestamp_seconds", "gauge", "最近一次成功统计收件箱的时间 (Unix 秒)")
+	repos := make([]string, 0, len(m.inboxRefreshed))
+	for repo := range m.inboxRefreshed {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		fmt.Fprintf(w, "github_issue_inbox_last_refresh_timestamp_seconds{repo=%s} %d\n", quoteLabel(repo), m.inboxRefreshed[repo].Unix())
+	}
+
+	writeMetricHeader(w, "github_issue_inbox_refresh_errors_total", "counter", "统计收件箱失败的次数")
+	writeCounterByLabel(w, "github_issue_inbox_refresh_errors_total", "repo", m.inboxErrors)
+}
+
+func writeMetricHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// writeCounterByLabel 输出只有一个标签的计数器，按标签值排序
+func writeCounterByLabel(w io.Writer, name, label string, values map[string]uint64) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{%s=%s} %d\n", name, label, quoteLabel(k), values[k])
+	}
+}
+
+// quoteLabel 按 Prometheus 文本格式转义标签值
+func quoteLabel(v string) string {
+	v = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+	return `"` + v + `"`
+}
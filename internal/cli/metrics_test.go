@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+)
+
+func TestMetricsWrite(t *testing.T) {
+	m := newMetricsRegistry()
+	m.started = time.Unix(1700000000, 0)
+
+	m.observeGitHub(github.RequestTrace{
+		Method: "GET", URL: "https://api.github.com/repos/o/r/issues/1",
+		Status: 200, Duration: 80 * time.Millisecond, RateLimitRemaining: "4999",
+	})
+	m.observeGitHub(github.RequestTrace{
+		Method: "GET", URL: "https://api.github.com/repos/o/r/issues/2",
+		Status: 404, Duration: 300 * time.Millisecond,
+	})
+	m.observeGitHub(github.RequestTrace{
+		Method: "POST", URL: "https://api.github.com/gists",
+		Duration: 2 * time.Second, Err: errors.New("timeout"),
+	})
+	m.observeToolCall("create_issue", false)
+	m.observeToolCall("create_issue", true)
+	m.observeToolCall("list_issues", false)
+	m.setInbox("o/r", map[string]int{"pending": 3, "processing": 0})
+	m.inboxFailed(`o/"quoted"`)
+
+	var b strings.Builder
+	m.write(&b)
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE github_issue_github_requests_total counter\n",
+		"github_issue_start_time_seconds 1700000000\n",
+		`github_issue_github_requests_total{endpoint="/gists",method="POST",status="error"} 1` + "\n" +
+			`github_issue_github_requests_total{endpoint="/repos/{owner}/{repo}/issues/{number}",method="GET",status="200"} 1` + "\n" +
+			`github_issue_github_requests_total{endpoint="/repos/{owner}/{repo}/issues/{number}",method="GET",status="404"} 1` + "\n",
+		`github_issue_github_request_duration_seconds_bucket{endpoint="/repos/{owner}/{repo}/issues/{number}",method="GET",le="0.05"} 0` + "\n" +
+			`github_issue_github_request_duration_seconds_bucket{endpoint="/repos/{owner}/{repo}/issues/{number}",method="GET",le="0.1"} 1` + "\n" +
+			`github_issue_github_request_duration_seconds_bucket{endpoint="/repos/{owner}/{repo}/issues/{number}",method="GET",le="0.25"} 1` + "\n" +
+			`github_issue_github_request_duration_seconds_bucket{endpoint="/repos/{owner}/{repo}/issues/{number}",method="GET",le="0.5"} 2` + "\n",
+		`github_issue_github_request_duration_seconds_bucket{endpoint="/repos/{owner}/{repo}/issues/{number}",method="GET",le="+Inf"} 2` + "\n" +
+			`github_issue_github_request_duration_seconds_sum{endpoint="/repos/{owner}/{repo}/issues/{number}",method="GET"} 0.38` + "\n" +
+			`github_issue_github_request_duration_seconds_count{endpoint="/repos/{owner}/{repo}/issues/{number}",method="GET"} 2` + "\n",
+		"github_issue_github_ratelimit_remaining 4999\n",
+		`github_issue_tool_calls_total{tool="create_issue"} 2` + "\n" + `github_issue_tool_calls_total{tool="list_issues"} 1` + "\n",
+		`github_issue_tool_errors_total{tool="create_issue"} 1` + "\n",
+		`github_issue_inbox_issues{repo="o/r",status="pending"} 3` + "\n" + `github_issue_inbox_issues{repo="o/r",status="processing"} 0` + "\n",
+		`github_issue_inbox_refresh_errors_total{repo="o/\"quoted\""} 1` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("指标输出缺少:\n%s\n完整输出:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `{endpoint="/repos/o/r`) {
+		t.Errorf("指标标签中不应包含仓库名:\n%s", out)
+	}
+}
+
+func TestMetricsWriteWithoutRateLimit(t *testing.T) {
+	m := newMetricsRegistry()
+	var b strings.Builder
+	m.write(&b)
+	if strings.Contains(b.String(), "github_issue_github_ratelimit_remaining") {
+		t.Error("没有收到配额信息时不应输出 ratelimit_remaining")
+	}
+}
+
+func TestMetricsNilRegistry(t *testing.T) {
+	var m *metricsRegistry
+	// 未启用指标时各记录方法都应是空操作
+	m.observeGitHub(github.RequestTrace{URL: "https://api.github.com/gists"})
+	m.observeToolCall("list_issues", true)
+	m.setInbox("o/r", map[string]int{"pending": 1})
+	m.inboxFailed("o/r")
+}
+
+func TestQuoteLabel(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", `"plain"`},
+		{`a"b`, `"a\"b"`},
+		{`a\b`, `"a\\b"`},
+		{"a\nb", `"a\nb"`},
+	}
+	for _, tt := range tests {
+		if got := quoteLabel(tt.in); got != tt.want {
+			t.Errorf("quoteLabel(%q) = %s, 期望 %s", tt.in, got, tt.want)
+		}
+	}
+}
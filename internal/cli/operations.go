@@ -0,0 +1,1228 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shichao402/github-issue-pack/internal/models"
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// operation CLI 命令与 MCP 工具共用的操作定义
+//
+// MCP 工具 github_issue_<Name> 的 inputSchema 由 Params 生成，CLI 命令和 MCP
+// 工具调用同一个 Run，参数校验（必需参数、枚举值）也在这里统一完成。
+// 新增功能时在 operations 中注册即可同时获得 MCP 工具，再按需用
+// operationCommand 生成 CLI 命令。
+type operation struct {
+	Name        string
+	Title       string // 动作名称，用于错误提示，如 "创建 Issue"
+	Description string
+	Params      []opParam
+	Output      *inputSchema
+	Run         func(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error)
+}
+
+// opParam 操作参数
+type opParam struct {
+	Name        string
+	Type        string // string/integer/boolean/object/array
+	Description string
+	Enum        []string
+	Items       *property // array 元素的 schema
+	Required    bool
+	Default     interface{} // 固定默认值，或 func() int 等在使用时才确定的默认值
+	Local       bool        // 读取 Server 本机的文件或环境，只在 CLI 和 stdio 传输中提供
+}
+
+// defaultValue 返回参数的默认值，Default 为函数时调用它取得当前值（如 profile 的 limit）
+func (p opParam) defaultValue() interface{} {
+	if fn, ok := p.Default.(func() int); ok {
+		return fn()
+	}
+	return p.Default
+}
+
+// exposed 参数是否注册为当前 MCP 传输的工具参数：HTTP 传输的调用方在远程，
+// 不能让其读取 Server 本机的目录或把 Server 的 git 信息写入 Issue
+func (p opParam) exposed() bool {
+	return !p.Local || serveTransport == "stdio"
+}
+
+// opResult 操作结果：Text 供人阅读，Structured 为 MCP structuredContent 和 CLI --format json 的输出
+type opResult struct {
+	Text       string
+	Structured interface{}
+	Value      interface{} // 服务层的原始结果，供 CLI 专有的输出格式渲染
+}
+
+// opInput 操作参数值，MCP 中为工具参数，CLI 中由 flag 和位置参数构建
+type opInput map[string]interface{}
+
+func (in opInput) str(name string) string {
+	s, _ := in[name].(string)
+	return s
+}
+
+func (in opInput) integer(name string) (int, error) {
+	return intArg(in, name)
+}
+
+// integerOr 读取整数参数，未提供时返回 def
+func (in opInput) integerOr(name string, def int) (int, error) {
+	if _, ok := in[name]; !ok {
+		return def, nil
+	}
+	return intArg(in, name)
+}
+
+func (in opInput) boolean(name string) bool {
+	switch v := in[name].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+func (in opInput) object(name string) (interface{}, error) {
+	return objectArg(in, name)
+}
+
+// stringList 读取字符串数组参数
+func (in opInput) stringList(name string) ([]string, error) {
+	switch v := in[name].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("参数 %s 必须是字符串数组", name)
+			}
+			items = append(items, s)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("参数 %s 必须是字符串数组", name)
+}
+
+// number 读取并校验 Issue 编号
+func (in opInput) number() (int, error) {
+	return issueNumberArg(in)
+}
+
+// attachments 读取附件数组 [{"name": ..., "content": ...}]
+func (in opInput) attachments(name string) ([]models.Attachment, error) {
+	raw, ok := in[name]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var attachments []models.Attachment
+	if err := json.Unmarshal(data, &attachments); err != nil {
+		return nil, fmt.Errorf("参数 %s 必须是 {name, content} 对象数组", name)
+	}
+	for _, att := range attachments {
+		if att.Name == "" {
+			return nil, fmt.Errorf("附件缺少 name")
+		}
+	}
+	return attachments, nil
+}
+
+// validate 检查必需参数和枚举值
+func (op *operation) validate(in opInput) error {
+	var missing []string
+	for _, p := range op.Params {
+		v, ok := in[p.Name]
+		if !ok || v == nil || v == "" {
+			if p.Required {
+				missing = append(missing, p.Name)
+			}
+			continue
+		}
+		if len(p.Enum) > 0 {
+			s, _ := v.(string)
+			if !containsString(p.Enum, s) {
+				return fmt.Errorf("无效的 %s: %v，只能是 %s", p.Name, v, strings.Join(p.Enum, "/"))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("缺少必需参数: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// tool 生成 MCP 工具定义，profile 配置了默认仓库时 repo 不再必需
+func (op *operation) tool() tool {
+	schema := inputSchema{Type: "object", Properties: map[string]property{}}
+	for _, p := range op.Params {
+		if !p.exposed() {
+			continue
+		}
+		schema.Properties[p.Name] = property{
+			Type:        p.Type,
+			Description: p.Description,
+			Enum:        p.Enum,
+			Items:       p.Items,
+			Default:     p.defaultValue(),
+		}
+		if p.Required && !(p.Name == "repo" && activeProfile.Repo != "") {
+			schema.Required = append(schema.Required, p.Name)
+		}
+	}
+	return tool{
+		Name:         "github_issue_" + op.Name,
+		Description:  op.Description,
+		InputSchema:  schema,
+		OutputSchema: op.Output,
+	}
+}
+
+// call 以 MCP 工具的方式执行操作
+func (op *operation) call(ctx context.Context, args map[string]interface{}) callToolResult {
+	in := opInput{}
+	for k, v := range args {
+		in[k] = v
+	}
+	for _, p := range op.Params {
+		if !p.exposed() {
+			delete(in, p.Name)
+		}
+	}
+	if repo := mcpRepo(args); repo != "" {
+		in["repo"] = repo
+	}
+	if err := op.validate(in); err != nil {
+		mcpLog(ctx, "warning", "tools", "工具参数无效", logFields{"tool": op.Name, "error": err.Error()})
+		return toolError("%v", err)
+	}
+
+	token := getMCPToken()
+	if token == "" {
+		mcpLog(ctx, "error", "tools", "无法获取 GitHub Token", logFields{"tool": op.Name})
+		return toolError("无法获取 GitHub Token，请设置 GITHUB_TOKEN 环境变量或运行 gh auth login")
+	}
+
+	result, err := op.Run(ctx, newMCPIssueService(ctx, token), in)
+	if err != nil {
+		mcpLog(ctx, "error", "tools", op.Title+" 失败", logFields{"tool": op.Name, "error": err.Error()})
+		return toolError("%s 失败: %v", op.Title, err)
+	}
+	return toolResult(result.Text, result.Structured)
+}
+
+// findOperation 按名称查找操作
+func findOperation(name string) *operation {
+	for _, op := range operations {
+		if op.Name == name {
+			return op
+		}
+	}
+	return nil
+}
+
+// runOperation 以 CLI 命令的方式执行操作，format 为 json 时输出结构化结果
+func runOperation(cmd *cobra.Command, name string, in opInput, format string) error {
+	result, err := execOperation(cmd, name, in)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		data, _ := json.MarshalIndent(result.Structured, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Println(strings.TrimRight(result.Text, "\n"))
+	return nil
+}
+
+// execOperation 校验参数并以 CLI 的 IssueService 执行操作
+func execOperation(cmd *cobra.Command, name string, in opInput) (*opResult, error) {
+	op := findOperation(name)
+	if err := op.validate(in); err != nil {
+		return nil, err
+	}
+	return op.Run(context.Background(), newIssueService(cmd), in)
+}
+
+// opRenderer 将操作结果渲染为 CLI 专有的输出格式
+type opRenderer func(result *opResult) ([]byte, error)
+
+// cliOptions operationCommand 生成的命令在 CLI 中的额外选项
+//
+// text 和 json 输出操作的 Text 和 Structured，yaml 和 --template 使用与 json
+// 相同的数据；表格、Markdown 等只在 CLI 中提供的格式由 Renderers 渲染。
+type cliOptions struct {
+	Formats   []string              // --format 可选值，第一个为默认值，默认 text/json
+	Renderers map[string]opRenderer // text/json/yaml 之外的格式
+	Data      func(result *opResult) interface{}
+	Defaults  map[string]interface{} // 参数在 CLI 中的默认值，覆盖 MCP 的默认值
+	Template  bool                   // 提供 --template
+	Output    bool                   // 提供 --output
+}
+
+// operationCommand 根据操作定义生成 CLI 命令
+//
+// positional 中的参数作为位置参数，其余 string/integer/boolean 参数生成同名
+// flag（下划线替换为连字符）；object 和 array 参数只在 MCP 中提供。
+func operationCommand(name, use, long string, positional ...string) *cobra.Command {
+	return operationCommandWith(name, use, long, cliOptions{}, positional...)
+}
+
+// operationCommandWith 根据操作定义和 CLI 选项生成命令
+//
+// 位置参数 repo 可以省略，此时使用 profile 的默认仓库。
+func operationCommandWith(name, use, long string, opts cliOptions, positional ...string) *cobra.Command {
+	op := findOperation(name)
+	minArgs := len(positional)
+	if containsString(positional, "repo") {
+		minArgs--
+	}
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: op.Description,
+		Long:  long,
+		Args:  cobra.RangeArgs(minArgs, len(positional)),
+	}
+
+	values := map[string]interface{}{}
+	for _, p := range op.Params {
+		if containsString(positional, p.Name) {
+			continue
+		}
+		flagName := strings.ReplaceAll(p.Name, "_", "-")
+		usage := p.Description
+		if len(p.Enum) > 0 {
+			usage = fmt.Sprintf("%s (%s)", usage, strings.Join(p.Enum, "/"))
+		}
+		def := p.defaultValue()
+		if v, ok := opts.Defaults[p.Name]; ok {
+			def = v
+		}
+		switch p.Type {
+		case "integer":
+			def, _ := def.(int)
+			values[p.Name] = cmd.Flags().Int(flagName, def, usage)
+		case "boolean":
+			def, _ := def.(bool)
+			values[p.Name] = cmd.Flags().Bool(flagName, def, usage)
+		case "string":
+			def, _ := def.(string)
+			values[p.Name] = cmd.Flags().String(flagName, def, usage)
+		default:
+			continue
+		}
+		if p.Required {
+			cmd.MarkFlagRequired(flagName)
+		}
+	}
+
+	formats := opts.Formats
+	if len(formats) == 0 {
+		formats = []string{"text", "json"}
+	}
+	format := new(string)
+	formatFlag(cmd.Flags(), format, "输出格式", formats...)
+	tmpl := new(string)
+	if opts.Template {
+		cmd.Flags().StringVar(tmpl, "template", "", "使用 Go 模板格式化输出，数据与 --format json 一致")
+	}
+	output := new(string)
+	if opts.Output {
+		cmd.Flags().StringVar(output, "output", "", "输出到文件")
+	}
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if !containsString(formats, *format) {
+			return fmt.Errorf("无效的输出格式: %s (%s)", *format, strings.Join(formats, "/"))
+		}
+
+		in := opInput{}
+		for i, arg := range args {
+			in[positional[i]] = arg
+		}
+		if containsString(positional, "repo") && in["repo"] == nil && activeProfile.Repo != "" {
+			in["repo"] = activeProfile.Repo
+		}
+		for name, v := range values {
+			switch v := v.(type) {
+			case *string:
+				if *v != "" {
+					in[name] = *v
+				}
+			case *int:
+				in[name] = float64(*v)
+			case *bool:
+				in[name] = *v
+			}
+		}
+
+		result, err := execOperation(cmd, op.Name, in)
+		if err != nil {
+			return err
+		}
+		data, err := opts.render(result, *format, *tmpl)
+		if err != nil {
+			return err
+		}
+		return writeOutput(*output, data)
+	}
+	return cmd
+}
+
+// render 按 --template 或 --format 渲染操作结果
+func (opts cliOptions) render(result *opResult, format, tmpl string) ([]byte, error) {
+	data := result.Structured
+	if opts.Data != nil {
+		data = opts.Data(result)
+	}
+	if tmpl != "" {
+		return renderTemplate(tmpl, data)
+	}
+	if render, ok := opts.Renderers[format]; ok {
+		return render(result)
+	}
+
+	switch format {
+	case "json":
+		out, _ := json.MarshalIndent(data, "", "  ")
+		return append(out, '\n'), nil
+	case "yaml":
+		return toYAML(data)
+	}
+	return []byte(strings.TrimRight(result.Text, "\n") + "\n"), nil
+}
+
+// writeOutput 将输出写入 --output 指定的文件，未指定时写到 stdout
+func writeOutput(path string, data []byte) error {
+	if path == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	fmt.Printf("已保存到 %s\n", path)
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// 常用参数
+var (
+	repoParam = opParam{
+		Name:        "repo",
+		Type:        "string",
+		Description: "目标仓库 (格式: owner/repo)",
+		Required:    true,
+	}
+	numberParam = opParam{
+		Name:        "number",
+		Type:        "integer",
+		Description: "Issue 编号",
+		Required:    true,
+	}
+	attachmentsParam = opParam{
+		Name:        "attachments",
+		Type:        "array",
+		Description: "附件，内容以文本内联",
+		Items: &property{
+			Type: "object",
+			Properties: map[string]property{
+				"name":    {Type: "string", Description: "附件文件名"},
+				"content": {Type: "string", Description: "附件内容"},
+			},
+			Required: []string{"name", "content"},
+		},
+	}
+	issueListOutput = &inputSchema{
+		Type: "object",
+		Properties: map[string]property{
+			"count": {Type: "integer", Description: "返回的 Issue 数量"},
+			"issues": {
+				Type: "array",
+				Items: &property{
+					Type: "object",
+					Properties: map[string]property{
+						"number":     {Type: "integer"},
+						"title":      {Type: "string"},
+						"type":       {Type: "string"},
+						"status":     {Type: "string"},
+						"created_at": {Type: "string"},
+						"url":        {Type: "string"},
+					},
+					Required: []string{"number", "title", "url"},
+				},
+			},
+		},
+		Required: []string{"count", "issues"},
+	}
+)
+
+// defaultListLimit 列表默认返回数量，profile 配置优先
+func defaultListLimit() int {
+	if activeProfile.Limit > 0 {
+		return activeProfile.Limit
+	}
+	return 20
+}
+
+// operations 所有操作，顺序即 MCP tools/list 的顺序
+var operations = []*operation{
+	{
+		Name:        "create",
+		Title:       "创建 Issue",
+		Description: "创建标准化的 GitHub Issue，自动打包内容和附件到 Gist；dry_run 时只返回预览",
+		Params: []opParam{
+			repoParam,
+			{Name: "type", Type: "string", Required: true, Description: "Issue 类型: feature-request/bug-report/pack-register/pack-sync/question/custom，或目标仓库在 .github/issue-pack/types 中注册的类型"},
+			{Name: "title", Type: "string", Required: true, Description: "Issue 标题"},
+			{Name: "payload", Type: "object", Description: "详细内容 (JSON 对象，兼容 JSON 字符串)"},
+			attachmentsParam,
+			{Name: "detect_metadata", Type: "boolean", Description: "从 Server 工作目录探测 git 仓库、提交、分支、系统和 CursorToolset 版本写入 meta", Default: false, Local: true},
+			{Name: "pack_dir", Type: "string", Description: "读取 package.json / version.json 中包名和版本的目录", Local: true},
+			{
+				Name:        "metadata",
+				Type:        "object",
+				Description: "覆盖 meta 来源信息，字段: " + strings.Join(service.MetadataKeys, ", ") + "；空字符串表示不写入该字段",
+			},
+			{Name: "dry_run", Type: "boolean", Description: "预览模式，不实际创建", Default: false},
+			{Name: "skip_duplicate_check", Type: "boolean", Description: "不检查与打开 Issue 的重复", Default: false},
+		},
+		Output: &inputSchema{
+			Type: "object",
+			Properties: map[string]property{
+				"number":    {Type: "integer", Description: "Issue 编号"},
+				"issue_url": {Type: "string", Description: "Issue 地址"},
+				"gist_url":  {Type: "string", Description: "Issue 包所在 Gist 地址"},
+				"dry_run":   {Type: "boolean"},
+				"labels":    {Type: "array", Items: &property{Type: "string"}, Description: "将要添加的标签 (dry_run)"},
+				"summary":   {Type: "string", Description: "Issue 摘要 (dry_run)"},
+				"package":   {Type: "object", Description: "将要上传的 IssuePackage (dry_run)"},
+				"duplicates": {
+					Type:        "array",
+					Description: "可能重复的打开 Issue (number, title, url, status, score, reason)，只作提示",
+					Items:       &property{Type: "object"},
+				},
+				"warnings": {
+					Type:        "array",
+					Description: "不影响创建的问题，如重复检查失败",
+					Items:       &property{Type: "string"},
+				},
+			},
+		},
+		Run: runCreateOperation,
+	},
+	{
+		Name:        "amend",
+		Title:       "修改 Issue 包",
+		Description: "修改已创建 Issue 的 payload 或附件：更新 Gist 生成新版本（旧版本保留），并在 Issue 下发表评论说明变更",
+		Params: []opParam{
+			repoParam,
+			numberParam,
+			{Name: "payload", Type: "object", Description: "新的 payload (完整替换，按类型 schema 重新校验)；省略时保持不变"},
+			attachmentsParam,
+			{Name: "remove_attachments", Type: "array", Description: "删除的附件名", Items: &property{Type: "string"}},
+			{Name: "comment", Type: "string", Description: "附在变更评论中的说明 (可选)"},
+			{Name: "dry_run", Type: "boolean", Description: "预览模式，只返回修改后的 IssuePackage", Default: false},
+		},
+		Output: &inputSchema{
+			Type: "object",
+			Properties: map[string]property{
+				"number":            {Type: "integer"},
+				"gist_url":          {Type: "string"},
+				"revision":          {Type: "string", Description: "新版本 SHA (dry_run 时省略)"},
+				"previous_revision": {Type: "string"},
+				"changed_fields":    {Type: "array", Items: &property{Type: "string"}, Description: "变化的 payload 顶层字段"},
+				"added_files":       {Type: "array", Items: &property{Type: "string"}},
+				"removed_files":     {Type: "array", Items: &property{Type: "string"}},
+				"dry_run":           {Type: "boolean"},
+				"package":           {Type: "object", Description: "修改后的 IssuePackage (dry_run)"},
+			},
+			Required: []string{"number"},
+		},
+		Run: runAmendOperation,
+	},
+	{
+		Name:        "list",
+		Title:       "列出 Issue",
+		Description: "列出仓库中的标准化 Issue",
+		Params: []opParam{
+			repoParam,
+			{Name: "status", Type: "string", Description: "状态过滤", Enum: []string{"pending", "processing", "needs-info", "processed", "rejected", "duplicate", "all"}},
+			{Name: "type", Type: "string", Description: "类型过滤 (内置类型或目标仓库注册的类型)"},
+			{Name: "limit", Type: "integer", Description: "返回数量限制 (默认取 profile 的 limit，未配置时为 20；0 表示全部)", Default: defaultListLimit},
+		},
+		Output: issueListOutput,
+		Run: func(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error) {
+			limit, err := in.integerOr("limit", defaultListLimit())
+			if err != nil {
+				return nil, err
+			}
+			issues, err := svc.List(service.ListOptions{
+				Repo:   in.str("repo"),
+				Status: in.str("status"),
+				Type:   in.str("type"),
+				Limit:  limit,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return issueListResult(issues), nil
+		},
+	},
+	{
+		Name:        "search",
+		Title:       "搜索 Issue",
+		Description: "按关键字搜索标准化 Issue，支持 GitHub 搜索语法 (如 author:xxx、in:title)",
+		Params: []opParam{
+			repoParam,
+			{Name: "query", Type: "string", Required: true, Description: "搜索关键字及 GitHub 搜索限定符"},
+			{Name: "status", Type: "string", Description: "状态过滤", Enum: []string{"pending", "processing", "needs-info", "processed", "rejected", "duplicate", "all"}},
+			{Name: "type", Type: "string", Description: "类型过滤 (内置类型或目标仓库注册的类型)"},
+			{Name: "limit", Type: "integer", Description: "返回数量限制 (默认取 profile 的 limit，未配置时为 20；0 表示全部)", Default: defaultListLimit},
+		},
+		Output: issueListOutput,
+		Run: func(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error) {
+			limit, err := in.integerOr("limit", defaultListLimit())
+			if err != nil {
+				return nil, err
+			}
+			issues, err := svc.Search(service.SearchOptions{
+				ListOptions: service.ListOptions{
+					Repo:   in.str("repo"),
+					Status: in.str("status"),
+					Type:   in.str("type"),
+					Limit:  limit,
+				},
+				Query: in.str("query"),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return issueListResult(issues), nil
+		},
+	},
+	{
+		Name:        "get",
+		Title:       "获取 Issue",
+		Description: "获取 Issue 详情，包括解析的 payload、附件和评论会话",
+		Params: []opParam{
+			repoParam,
+			numberParam,
+			{Name: "comments", Type: "boolean", Description: "包含评论会话", Default: true},
+			{Name: "revision", Type: "string", Description: "Issue 包版本：SHA（至少 4 位）或序号（1 为最初版本），默认最新"},
+			{Name: "diff", Type: "boolean", Description: "同时返回该版本相对上一版本的 unified diff", Default: false},
+		},
+		Output: &inputSchema{
+			Type: "object",
+			Properties: map[string]property{
+				"issue": {
+					Type: "object",
+					Properties: map[string]property{
+						"number":     {Type: "integer"},
+						"title":      {Type: "string"},
+						"state":      {Type: "string"},
+						"url":        {Type: "string"},
+						"created_at": {Type: "string"},
+						"updated_at": {Type: "string"},
+					},
+					Required: []string{"number", "title", "state", "url"},
+				},
+				"package": {
+					Type:        "object",
+					Description: "解包后的 IssuePackage（含附件内容），Issue 没有关联的包时省略",
+				},
+				"comments": {
+					Type:        "array",
+					Description: "评论会话（按时间升序），status 非空的是工具生成的状态评论",
+					Items:       &property{Type: "object"},
+				},
+				"revision": {Type: "string", Description: "package 对应的 Issue 包版本 (Gist 版本 SHA)"},
+				"revisions": {
+					Type:        "array",
+					Description: "Issue 包的全部版本，最新的在前；number 为序号，1 为最初版本",
+					Items:       &property{Type: "object"},
+				},
+				"diff": {Type: "string", Description: "该版本相对上一版本的差异 (diff 为 true 时)"},
+			},
+			Required: []string{"issue"},
+		},
+		Run: func(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error) {
+			number, err := in.number()
+			if err != nil {
+				return nil, err
+			}
+			result, err := svc.GetRevision(in.str("repo"), number, in.str("revision"))
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := in["comments"]; !ok || in.boolean("comments") {
+				if result.Comments, err = svc.Comments(in.str("repo"), number); err != nil {
+					return nil, err
+				}
+			}
+
+			var text string
+			text = fmt.Sprintf("Issue #%d: %s\n\n", result.Issue.Number, result.Issue.Title)
+			text += fmt.Sprintf("状态: %s\n", result.Issue.State)
+			text += fmt.Sprintf("创建时间: %s\n", result.Issue.CreatedAt)
+			text += fmt.Sprintf("URL: %s\n", result.Issue.HTMLURL)
+
+			if result.Package != nil {
+				text += fmt.Sprintf("\n类型: %s\n", result.Package.Type)
+				if len(result.Revisions) > 1 {
+					text += fmt.Sprintf("版本: %s\n", formatRevisions(result))
+				}
+				if result.Package.Payload != nil {
+					payloadJSON, _ := json.MarshalIndent(result.Package.Payload, "", "  ")
+					text += fmt.Sprintf("\nPayload:\n%s\n", string(payloadJSON))
+				}
+				if len(result.Package.Attachments) > 0 {
+					text += "\n附件:\n"
+					for _, att := range result.Package.Attachments {
+						text += fmt.Sprintf("- %s (%d 字节)\n", att.Name, len(att.Content))
+					}
+				}
+			}
+			if len(result.Comments) > 0 {
+				text += fmt.Sprintf("\n评论 (%d):\n%s", len(result.Comments), formatThread(result.Comments))
+			}
+
+			output := buildGetOutput(result)
+			if in.boolean("diff") {
+				diff, err := revisionDiff(svc, in.str("repo"), number, result.Revision)
+				if err != nil {
+					return nil, err
+				}
+				text += "\n差异:\n" + diff
+				output["diff"] = diff
+			}
+			return &opResult{Text: text, Structured: output, Value: result}, nil
+		},
+	},
+	{
+		Name:        "comments",
+		Title:       "获取评论",
+		Description: "列出 Issue 的全部评论，工具生成的状态评论带有 status 字段",
+		Params:      []opParam{repoParam, numberParam},
+		Output: &inputSchema{
+			Type: "object",
+			Properties: map[string]property{
+				"count": {Type: "integer"},
+				"comments": {
+					Type: "array",
+					Items: &property{
+						Type: "object",
+						Properties: map[string]property{
+							"id":                 {Type: "integer"},
+							"author":             {Type: "string"},
+							"author_association": {Type: "string"},
+							"body":               {Type: "string"},
+							"created_at":         {Type: "string"},
+							"url":                {Type: "string"},
+							"status":             {Type: "string", Description: "工具生成的状态评论设置的状态，人工评论省略"},
+							"reminder":           {Type: "boolean", Description: "stale 发表的催促评论"},
+						},
+						Required: []string{"id", "author", "body", "created_at"},
+					},
+				},
+			},
+			Required: []string{"count", "comments"},
+		},
+		Run: func(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error) {
+			number, err := in.number()
+			if err != nil {
+				return nil, err
+			}
+			comments, err := svc.Comments(in.str("repo"), number)
+			if err != nil {
+				return nil, err
+			}
+
+			text := "该 Issue 没有评论\n"
+			if len(comments) > 0 {
+				text = fmt.Sprintf("共 %d 条评论:\n%s", len(comments), formatThread(comments))
+			}
+			return &opResult{
+				Text:       text,
+				Structured: map[string]interface{}{"count": len(comments), "comments": comments},
+			}, nil
+		},
+	},
+	{
+		Name:        "reply",
+		Title:       "回复 Issue",
+		Description: "在 Issue 下发表评论，不改变状态",
+		Params: []opParam{
+			repoParam,
+			numberParam,
+			{Name: "body", Type: "string", Required: true, Description: "回复内容 (Markdown)"},
+		},
+		Output: &inputSchema{
+			Type:       "object",
+			Properties: map[string]property{"number": {Type: "integer"}},
+			Required:   []string{"number"},
+		},
+		Run: func(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error) {
+			number, err := in.number()
+			if err != nil {
+				return nil, err
+			}
+			if err := svc.Reply(in.str("repo"), number, in.str("body")); err != nil {
+				return nil, err
+			}
+			return &opResult{
+				Text:       fmt.Sprintf("✅ 已回复 Issue #%d", number),
+				Structured: map[string]interface{}{"number": number},
+			}, nil
+		},
+	},
+	{
+		Name:        "update",
+		Title:       "更新 Issue",
+		Description: "更新 Issue 状态；needs-info 表示需要发起人补充信息，comment 为向发起人提出的问题",
+		Params: []opParam{
+			repoParam,
+			numberParam,
+			{Name: "status", Type: "string", Required: true, Description: "新状态", Enum: []string{"pending", "processing", "needs-info"}},
+			{Name: "comment", Type: "string", Description: "添加评论 (needs-info 时必需)"},
+		},
+		Output: &inputSchema{
+			Type: "object",
+			Properties: map[string]property{
+				"number": {Type: "integer"},
+				"status": {Type: "string"},
+			},
+			Required: []string{"number", "status"},
+		},
+		Run: func(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error) {
+			number, err := in.number()
+			if err != nil {
+				return nil, err
+			}
+			status := in.str("status")
+			if err := svc.UpdateStatus(in.str("repo"), number, status, in.str("comment")); err != nil {
+				return nil, err
+			}
+			return &opResult{
+				Text:       fmt.Sprintf("✅ Issue #%d 状态已更新为 %s", number, status),
+				Structured: map[string]interface{}{"number": number, "status": status},
+			}, nil
+		},
+	},
+	{
+		Name:        "close",
+		Title:       "关闭 Issue",
+		Description: "关闭 Issue 并标记处理结果",
+		Params: []opParam{
+			repoParam,
+			numberParam,
+			{Name: "result", Type: "string", Required: true, Description: "处理结果", Enum: []string{"success", "rejected", "duplicate"}},
+			{Name: "duplicate_of", Type: "integer", Description: "规范 Issue 编号 (result 为 duplicate 时必填)"},
+			{Name: "comment", Type: "string", Description: "关闭说明 (可选)"},
+		},
+		Output: &inputSchema{
+			Type: "object",
+			Properties: map[string]property{
+				"number":       {Type: "integer"},
+				"result":       {Type: "string"},
+				"status":       {Type: "string"},
+				"duplicate_of": {Type: "integer", Description: "规范 Issue 编号 (duplicate)"},
+			},
+			Required: []string{"number", "result", "status"},
+		},
+		Run: func(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error) {
+			number, err := in.number()
+			if err != nil {
+				return nil, err
+			}
+			result := in.str("result")
+			if result == "duplicate" {
+				canonical, err := in.integerOr("duplicate_of", 0)
+				if err != nil {
+					return nil, err
+				}
+				if canonical == 0 {
+					return nil, fmt.Errorf("result 为 duplicate 时必须提供 duplicate_of")
+				}
+				if err := svc.CloseDuplicate(in.str("repo"), number, canonical, in.str("comment")); err != nil {
+					return nil, err
+				}
+				return &opResult{
+					Text:       fmt.Sprintf("✅ Issue #%d 已关闭，状态: duplicate (重复于 #%d)", number, canonical),
+					Structured: map[string]interface{}{"number": number, "result": result, "status": "duplicate", "duplicate_of": canonical},
+				}, nil
+			}
+			if err := svc.Close(in.str("repo"), number, result, in.str("comment")); err != nil {
+				return nil, err
+			}
+
+			status := "processed"
+			if result == "rejected" {
+				status = "rejected"
+			}
+			return &opResult{
+				Text:       fmt.Sprintf("✅ Issue #%d 已关闭，状态: %s", number, status),
+				Structured: map[string]interface{}{"number": number, "result": result, "status": status},
+			}, nil
+		},
+	},
+	{
+		Name:        "discover",
+		Title:       "读取仓库 manifest",
+		Description: "查看目标仓库接受哪些 Issue 包（manifest 与类型注册表）",
+		Params:      []opParam{repoParam},
+		Output: &inputSchema{
+			Type: "object",
+			Properties: map[string]property{
+				"repo":          {Type: "string"},
+				"accepts_packs": {Type: "boolean", Description: "仓库是否发布了 manifest 或类型注册表"},
+				"manifest":      {Type: "object", Description: "仓库 manifest，未发布时省略"},
+				"types":         {Type: "array", Items: &property{Type: "string"}},
+				"invalid_types": {Type: "array", Items: &property{Type: "string"}},
+			},
+			Required: []string{"repo", "accepts_packs", "types"},
+		},
+		Run: func(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error) {
+			repo := in.str("repo")
+			result, err := svc.Discover(repo)
+			if err != nil {
+				return nil, err
+			}
+
+			return &opResult{Text: discoverText(result), Structured: result}, nil
+		},
+	},
+	{
+		Name:        "labels",
+		Title:       "查看标签",
+		Description: "显示仓库生效的标签映射",
+		Params:      []opParam{repoParam},
+		Output: &inputSchema{
+			Type: "object",
+			Properties: map[string]property{
+				"labels": {
+					Type: "array",
+					Items: &property{
+						Type: "object",
+						Properties: map[string]property{
+							"name":        {Type: "string"},
+							"color":       {Type: "string"},
+							"description": {Type: "string"},
+						},
+						Required: []string{"name", "color"},
+					},
+				},
+			},
+			Required: []string{"labels"},
+		},
+		Run: func(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error) {
+			var types []string
+			for _, t := range models.BuiltinTypes {
+				types = append(types, string(t))
+			}
+			specs := svc.Labels(in.str("repo")).Specs(types)
+
+			var b strings.Builder
+			fmt.Fprintf(&b, "仓库 %s 的标签 (%d):\n", in.str("repo"), len(specs))
+			for _, spec := range specs {
+				fmt.Fprintf(&b, "- %s (#%s) %s\n", spec.Name, spec.Color, spec.Description)
+			}
+			return &opResult{Text: b.String(), Structured: map[string]interface{}{"labels": specs}, Value: specs}, nil
+		},
+	},
+	{
+		Name:        "labels_sync",
+		Title:       "同步标签",
+		Description: "在仓库中创建标签映射所需但缺失的标签",
+		Params: []opParam{
+			repoParam,
+			{Name: "dry_run", Type: "boolean", Description: "预览模式，只列出将要创建的标签", Default: false},
+		},
+		Output: &inputSchema{
+			Type: "object",
+			Properties: map[string]property{
+				"created": {Type: "array", Items: &property{Type: "object"}, Description: "新建（dry_run 时为将要新建）的标签"},
+				"existed": {Type: "array", Items: &property{Type: "object"}, Description: "已存在的标签"},
+			},
+			Required: []string{"created", "existed"},
+		},
+		Run: func(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error) {
+			dryRun := in.boolean("dry_run")
+			result, err := svc.SyncLabels(in.str("repo"), dryRun)
+			if err != nil {
+				return nil, err
+			}
+
+			var b strings.Builder
+			if dryRun {
+				b.WriteString("=== Dry Run 模式 ===\n")
+			}
+			for _, spec := range result.Created {
+				if dryRun {
+					fmt.Fprintf(&b, "将创建: %s (#%s)\n", spec.Name, spec.Color)
+				} else {
+					fmt.Fprintf(&b, "✅ 已创建: %s (#%s)\n", spec.Name, spec.Color)
+				}
+			}
+			fmt.Fprintf(&b, "新建 %d 个标签，已存在 %d 个\n", len(result.Created), len(result.Existed))
+			return &opResult{Text: b.String(), Structured: result}, nil
+		},
+	},
+}
+
+// runCreateOperation 创建 Issue 或返回 dry run 预览
+func runCreateOperation(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error) {
+	title := in.str("title")
+	payload, err := in.object("payload")
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		// 使用默认 payload
+		payload = map[string]string{
+			"title":       title,
+			"description": "",
+		}
+	}
+	attachments, err := in.attachments("attachments")
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := createMetadata(in)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := svc.Create(service.CreateIssueOptions{
+		Repo:        in.str("repo"),
+		Type:        models.IssueType(in.str("type")),
+		Title:       title,
+		Payload:     payload,
+		Attachments: attachments,
+		Metadata:    metadata,
+		DryRun:      in.boolean("dry_run"),
+
+		SkipDuplicateCheck: in.boolean("skip_duplicate_check"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	duplicates := formatDuplicates(result.Duplicates) + formatWarnings(result.Warnings)
+
+	if p := result.Preview; p != nil {
+		pkgJSON, _ := p.Package.ToJSON()
+		var b strings.Builder
+		b.WriteString("=== Dry Run 模式 ===\n")
+		fmt.Fprintf(&b, "目标仓库: %s\n", p.Repo)
+		fmt.Fprintf(&b, "Issue 类型: %s\n", p.Type)
+		fmt.Fprintf(&b, "标题: %s\n", p.Title)
+		fmt.Fprintf(&b, "标签: %s\n", strings.Join(p.Labels, ", "))
+		if p.Summary != "" {
+			fmt.Fprintf(&b, "\n=== Issue 摘要 ===\n%s\n", p.Summary)
+		}
+		fmt.Fprintf(&b, "\n=== Gist 内容 ===\n%s\n", pkgJSON)
+		b.WriteString(duplicates)
+
+		return &opResult{
+			Text: b.String(),
+			Structured: map[string]interface{}{
+				"dry_run":    true,
+				"labels":     p.Labels,
+				"summary":    p.Summary,
+				"package":    p.Package,
+				"duplicates": nonNilDuplicates(result.Duplicates),
+				"warnings":   nonNilStrings(result.Warnings),
+			},
+		}, nil
+	}
+
+	return &opResult{
+		Text: fmt.Sprintf("✅ Issue 创建成功!\n   Issue: %s\n   Gist:  %s", result.IssueURL, result.GistURL) + duplicates,
+		Structured: map[string]interface{}{
+			"number":     result.IssueNum,
+			"issue_url":  result.IssueURL,
+			"gist_url":   result.GistURL,
+			"duplicates": nonNilDuplicates(result.Duplicates),
+			"warnings":   nonNilStrings(result.Warnings),
+		},
+	}, nil
+}
+
+// formatDuplicates 输出可能重复的 Issue 提示，没有时返回空字符串
+func formatDuplicates(duplicates []service.DuplicateCandidate) string {
+	if len(duplicates) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\n⚠️  发现可能重复的打开 Issue:\n")
+	for _, d := range duplicates {
+		fmt.Fprintf(&b, "   #%d %s (相似度 %.2f，%s)\n      %s\n", d.Number, d.Title, d.Score, d.Reason, d.URL)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatWarnings 输出警告，没有时返回空字符串
+func formatWarnings(warnings []string) string {
+	var b strings.Builder
+	for _, w := range warnings {
+		fmt.Fprintf(&b, "\n⚠️  %s", w)
+	}
+	return b.String()
+}
+
+func nonNilDuplicates(duplicates []service.DuplicateCandidate) []service.DuplicateCandidate {
+	if duplicates == nil {
+		return []service.DuplicateCandidate{}
+	}
+	return duplicates
+}
+
+func runAmendOperation(ctx context.Context, svc *service.IssueService, in opInput) (*opResult, error) {
+	number, err := in.number()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := in.object("payload")
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := in.attachments("attachments")
+	if err != nil {
+		return nil, err
+	}
+	remove, err := in.stringList("remove_attachments")
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := svc.Amend(service.AmendOptions{
+		Repo:              in.str("repo"),
+		Number:            number,
+		Payload:           payload,
+		Attachments:       attachments,
+		RemoveAttachments: remove,
+		Comment:           in.str("comment"),
+		DryRun:            in.boolean("dry_run"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	structured := map[string]interface{}{
+		"number":            result.Number,
+		"gist_url":          result.GistURL,
+		"previous_revision": result.PreviousRevision,
+		"changed_fields":    nonNilStrings(result.ChangedFields),
+		"added_files":       nonNilStrings(result.AddedFiles),
+		"removed_files":     nonNilStrings(result.RemovedFiles),
+	}
+
+	dryRun := in.boolean("dry_run")
+	var b strings.Builder
+	if dryRun {
+		b.WriteString("=== Dry Run 模式 ===\n")
+		structured["dry_run"] = true
+		structured["package"] = result.Package
+	} else {
+		fmt.Fprintf(&b, "✅ Issue #%d 的 Issue 包已更新，版本 %s\n", number, shortRevision(result.Revision))
+		structured["revision"] = result.Revision
+	}
+	if len(result.ChangedFields) > 0 {
+		fmt.Fprintf(&b, "变更字段: %s\n", strings.Join(result.ChangedFields, ", "))
+	}
+	if len(result.AddedFiles) > 0 {
+		fmt.Fprintf(&b, "新增/更新附件: %s\n", strings.Join(result.AddedFiles, ", "))
+	}
+	if len(result.RemovedFiles) > 0 {
+		fmt.Fprintf(&b, "删除附件: %s\n", strings.Join(result.RemovedFiles, ", "))
+	}
+	if dryRun {
+		pkgJSON, _ := result.Package.ToJSON()
+		fmt.Fprintf(&b, "\n=== 修改后的 Gist 内容 ===\n%s\n", pkgJSON)
+	}
+	return &opResult{Text: b.String(), Structured: structured}, nil
+}
+
+// nonNilStrings 将 nil 切片转换为空切片，使 JSON 输出为 [] 而不是 null
+func nonNilStrings(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
+
+// issueListResult list 和 search 共用的结果格式
+func issueListResult(issues []service.IssueInfo) *opResult {
+	items := issues
+	if items == nil {
+		items = []service.IssueInfo{}
+	}
+	structured := map[string]interface{}{"count": len(issues), "issues": items}
+
+	if len(issues) == 0 {
+		return &opResult{Text: "没有找到匹配的 Issue", Structured: structured, Value: items}
+	}
+
+	var text string
+	text = fmt.Sprintf("找到 %d 个 Issue:\n\n", len(issues))
+	for _, issue := range issues {
+		text += fmt.Sprintf("- #%d [%s] %s (%s)\n  %s\n", issue.Number, issue.Status, issue.Title, issue.Type, issue.URL)
+	}
+	return &opResult{Text: text, Structured: structured, Value: items}
+}
+
+// createMetadata 按 detect_metadata 和 pack_dir 探测来源信息，再应用 metadata 中的覆盖值
+func createMetadata(in opInput) (*service.SourceMetadata, error) {
+	metadata := &service.SourceMetadata{}
+	if in.boolean("detect_metadata") {
+		metadata = service.DetectMetadata(".", in.str("pack_dir"))
+	} else if dir := in.str("pack_dir"); dir != "" {
+		metadata.Pack, metadata.PackVersion = service.ReadPackVersion(dir)
+	}
+
+	raw, err := in.object("metadata")
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return metadata, nil
+	}
+	overrides, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("metadata 必须是对象")
+	}
+	for key, v := range overrides {
+		value, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("metadata.%s 必须是字符串", key)
+		}
+		if err := metadata.Set(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return metadata, nil
+}
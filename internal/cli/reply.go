@@ -0,0 +1,10 @@
+package cli
+
+var replyCmd = operationCommand("reply", "reply <issue-number>", `在 Issue 下发表评论，不改变状态。
+
+示例:
+  github-issue reply 123 --repo owner/repo --body "已收到，正在排查"`, "number")
+
+func init() {
+	rootCmd.AddCommand(replyCmd)
+}
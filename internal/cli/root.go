@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
+	"github.com/shichao402/github-issue-pack/internal/config"
+	"github.com/shichao402/github-issue-pack/internal/github"
+	"github.com/shichao402/github-issue-pack/internal/service"
 	"github.com/spf13/cobra"
 )
 
@@ -18,16 +22,112 @@ var rootCmd = &cobra.Command{
 
 Token 获取优先级:
   1. --token 参数
-  2. GITHUB_TOKEN 环境变量
-  3. gh CLI 认证信息 (自动获取)`,
+  2. 当前 profile 配置的 token 来源
+  3. GITHUB_TOKEN 环境变量
+  4. gh CLI 认证信息 (自动获取)
+
+配置文件:
+  全局: ~/.config/github-issue/config.yaml
+  项目: .github-issue.yaml (从当前目录向上查找，覆盖全局配置)
+  使用 --profile 或 GITHUB_ISSUE_PROFILE 选择 profile`,
+	PersistentPreRunE: loadProfile,
 }
 
+// activeConfig 当前加载的配置，activeProfile 为其中生效的 profile
+var (
+	activeConfig  = &config.Config{}
+	activeProfile config.Profile
+)
+
 func Execute() error {
 	return rootCmd.Execute()
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringP("token", "t", "", "GitHub Token (可选，默认使用 gh CLI 认证)")
+	rootCmd.PersistentFlags().String("profile", "", "使用配置文件中的指定 profile")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "不使用本地 API 响应缓存")
+}
+
+// noCache 为 true 时不使用本地 API 响应缓存
+var noCache bool
+
+// loadProfile 加载配置文件并选择 profile，用 profile 填充未显式指定的通用参数
+func loadProfile(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	for _, warning := range cfg.Warnings {
+		fmt.Fprintf(os.Stderr, "警告: %s\n", warning)
+	}
+
+	name, _ := cmd.Flags().GetString("profile")
+	profile, err := cfg.Profile(name)
+	if err != nil {
+		return err
+	}
+	activeConfig = cfg
+	activeProfile = profile
+
+	defaults := map[string]string{
+		"repo":   profile.Repo,
+		"format": profile.Format,
+	}
+	if profile.Limit > 0 {
+		defaults["limit"] = strconv.Itoa(profile.Limit)
+	}
+	for name, value := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed || value == "" {
+			continue
+		}
+		// 各命令接受的输出格式不同，profile 的 format 只用于接受该格式的命令
+		if name == "format" && !acceptsFormat(flag, value) {
+			continue
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("profile 中的 %s 无效: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// serviceConfig 根据当前 profile 构建 IssueService 配置
+func serviceConfig(token string) service.Config {
+	repoLabels := make(map[string]service.Labels, len(activeConfig.Repos))
+	for repo, rc := range activeConfig.Repos {
+		repoLabels[repo] = rc.Labels
+	}
+
+	return service.Config{
+		Token:      token,
+		APIHost:    activeProfile.APIHost,
+		Labels:     activeProfile.Labels,
+		RepoLabels: repoLabels,
+		CacheDir:   cacheDir(),
+	}
+}
+
+// cacheDir 返回 API 响应缓存目录，--no-cache 或无法确定目录时返回空
+func cacheDir() string {
+	if noCache {
+		return ""
+	}
+	dir, err := github.DefaultCacheDir()
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
+// newIssueService 创建使用当前 token 和 profile 的 IssueService
+func newIssueService(cmd *cobra.Command) *service.IssueService {
+	return service.NewIssueServiceWithConfig(serviceConfig(getToken(cmd))).
+		WithWarnings(func(message string) {
+			fmt.Fprintf(os.Stderr, "警告: %s\n", message)
+		})
 }
 
 func getToken(cmd *cobra.Command) string {
@@ -37,13 +137,22 @@ func getToken(cmd *cobra.Command) string {
 		return token
 	}
 
-	// 2. 环境变量
+	// 2. profile 配置的 token 来源
+	token, err := activeProfile.ResolveToken(getGhToken)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: %v\n", err)
+	}
+	if token != "" {
+		return token
+	}
+
+	// 3. 环境变量
 	token = os.Getenv("GITHUB_TOKEN")
 	if token != "" {
 		return token
 	}
 
-	// 3. 从 gh CLI 获取
+	// 4. 从 gh CLI 获取
 	token = getGhToken()
 	if token != "" {
 		return token
@@ -52,8 +161,9 @@ func getToken(cmd *cobra.Command) string {
 	fmt.Fprintln(os.Stderr, "错误: 无法获取 GitHub Token")
 	fmt.Fprintln(os.Stderr, "请使用以下任一方式提供认证:")
 	fmt.Fprintln(os.Stderr, "  1. --token 参数")
-	fmt.Fprintln(os.Stderr, "  2. 设置 GITHUB_TOKEN 环境变量")
-	fmt.Fprintln(os.Stderr, "  3. 运行 'gh auth login' 进行认证")
+	fmt.Fprintln(os.Stderr, "  2. 在配置文件的 profile 中设置 token 来源")
+	fmt.Fprintln(os.Stderr, "  3. 设置 GITHUB_TOKEN 环境变量")
+	fmt.Fprintln(os.Stderr, "  4. 运行 'gh auth login' 进行认证")
 	os.Exit(1)
 	return ""
 }
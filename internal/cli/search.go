@@ -0,0 +1,14 @@
+package cli
+
+var searchCmd = operationCommand("search", "search <query>", `在标准化 Issue 中搜索，支持 GitHub 搜索语法。
+
+自动限定仓库和标记标签，--status/--type 与 list 命令相同。
+
+示例:
+  github-issue search "crash on startup" --repo owner/repo
+  github-issue search "author:octocat in:title" --repo owner/repo --status all
+  github-issue search timeout --repo owner/repo --type bug-report --format json`, "query")
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+}
@@ -2,11 +2,15 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/shichao402/github-issue-pack/internal/models"
 	"github.com/shichao402/github-issue-pack/internal/service"
 	"github.com/spf13/cobra"
 )
@@ -16,15 +20,42 @@ var serveCmd = &cobra.Command{
 	Short: "启动 MCP Server 模式",
 	Long: `启动 GitHub Issue Pack 的 MCP Server 模式，供 Cursor 等 IDE 调用。
 
-此命令通过 stdin/stdout 与 IDE 通信，使用 JSON-RPC 2.0 协议。
+默认通过 stdin/stdout 与 IDE 通信，使用 JSON-RPC 2.0 协议。
+使用 --transport http 时以 MCP Streamable HTTP 传输 (POST + SSE) 提供服务，
+多个 IDE 和远程 Agent 可共享同一个 Server 及其 GitHub Token。
 
 示例：
-  github-issue serve`,
+  github-issue serve
+  github-issue serve --transport http --listen :8080 --auth-token <secret>
+  github-issue serve --metrics-listen 127.0.0.1:9464 --metrics-repo owner/repo`,
 	RunE: runServe,
 }
 
+var (
+	serveTransport string
+	serveListen    string
+	serveAuthToken string
+	serveInsecure  bool
+	serveLogFile   string
+	serveLogLevel  string
+
+	serveMetricsListen   string
+	serveMetricsRepos    []string
+	serveMetricsInterval time.Duration
+)
+
 func init() {
 	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveTransport, "transport", "stdio", "传输方式 (stdio/http)")
+	serveCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:8080", "HTTP 监听地址 (--transport http)")
+	serveCmd.Flags().StringVar(&serveAuthToken, "auth-token", "", "HTTP 客户端需提供的 Bearer Token (默认读取 "+mcpAuthTokenEnv+" 环境变量)")
+	serveCmd.Flags().BoolVar(&serveInsecure, "insecure-no-auth", false, "允许在非本机地址上不设置 --auth-token 监听（任何能访问端口的人都可以使用本 Server 的 GitHub Token）")
+	serveCmd.Flags().StringVar(&serveLogFile, "log-file", "", "诊断日志文件 (JSON Lines)，记录请求耗时和 GitHub API 调用")
+	serveCmd.Flags().StringVar(&serveLogLevel, "log-level", "info", "日志文件的最低级别 (debug/info/notice/warning/error)，debug 包含 GitHub API 调用")
+	serveCmd.Flags().StringVar(&serveMetricsListen, "metrics-listen", "", "Prometheus 指标端点的监听地址 (如 127.0.0.1:9464)，默认不启用")
+	serveCmd.Flags().StringSliceVar(&serveMetricsRepos, "metrics-repo", nil, "统计收件箱深度的仓库，可重复指定 (默认为 profile 的默认仓库)")
+	serveCmd.Flags().DurationVar(&serveMetricsInterval, "metrics-interval", 5*time.Minute, "统计收件箱深度的间隔")
 }
 
 // MCP JSON-RPC 消息类型
@@ -56,7 +87,10 @@ type initializeResult struct {
 }
 
 type capabilities struct {
-	Tools *toolsCapability `json:"tools,omitempty"`
+	Tools     *toolsCapability     `json:"tools,omitempty"`
+	Resources *resourcesCapability `json:"resources,omitempty"`
+	Prompts   *promptsCapability   `json:"prompts,omitempty"`
+	Logging   *loggingCapability   `json:"logging,omitempty"`
 }
 
 type toolsCapability struct {
@@ -69,9 +103,10 @@ type serverInfo struct {
 }
 
 type tool struct {
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	InputSchema inputSchema `json:"inputSchema"`
+	Name         string       `json:"name"`
+	Description  string       `json:"description"`
+	InputSchema  inputSchema  `json:"inputSchema"`
+	OutputSchema *inputSchema `json:"outputSchema,omitempty"`
 }
 
 type inputSchema struct {
@@ -81,9 +116,13 @@ type inputSchema struct {
 }
 
 type property struct {
-	Type        string   `json:"type"`
-	Description string   `json:"description"`
-	Enum        []string `json:"enum,omitempty"`
+	Type        string              `json:"type"`
+	Description string              `json:"description,omitempty"`
+	Enum        []string            `json:"enum,omitempty"`
+	Items       *property           `json:"items,omitempty"`
+	Properties  map[string]property `json:"properties,omitempty"`
+	Required    []string            `json:"required,omitempty"`
+	Default     interface{}         `json:"default,omitempty"`
 }
 
 type toolsListResult struct {
@@ -96,8 +135,9 @@ type callToolParams struct {
 }
 
 type callToolResult struct {
-	Content []contentItem `json:"content"`
-	IsError bool          `json:"isError,omitempty"`
+	Content           []contentItem `json:"content"`
+	StructuredContent interface{}   `json:"structuredContent,omitempty"`
+	IsError           bool          `json:"isError,omitempty"`
 }
 
 type contentItem struct {
@@ -106,9 +146,77 @@ type contentItem struct {
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
+	if serveLogFile != "" {
+		logger, err := openServerLog(serveLogFile, serveLogLevel)
+		if err != nil {
+			return err
+		}
+		serveLog = logger
+		serveLog.log("info", "server", "MCP Server 启动", logFields{
+			"version":   Version,
+			"transport": serveTransport,
+		})
+	}
+
+	if serveMetricsListen != "" {
+		if err := startServeMetrics(); err != nil {
+			return err
+		}
+	}
+
+	switch serveTransport {
+	case "stdio":
+		return runStdioServer()
+	case "http":
+		authToken := serveAuthToken
+		if authToken == "" {
+			authToken = os.Getenv(mcpAuthTokenEnv)
+		}
+		return runHTTPServer(serveListen, authToken, serveInsecure)
+	default:
+		return fmt.Errorf("无效的传输方式: %s，只能是 stdio 或 http", serveTransport)
+	}
+}
+
+// startServeMetrics 启动指标端点，并在后台定期统计收件箱深度
+func startServeMetrics() error {
+	if serveMetricsInterval < time.Minute {
+		return fmt.Errorf("--metrics-interval 不能小于 1m")
+	}
+	if err := startMetricsServer(serveMetricsListen); err != nil {
+		return err
+	}
+
+	repos := serveMetricsRepos
+	if len(repos) == 0 && activeProfile.Repo != "" {
+		repos = []string{activeProfile.Repo}
+	}
+	if len(repos) == 0 {
+		return nil
+	}
+	token := getMCPToken()
+	if token == "" {
+		fmt.Fprintln(os.Stderr, "警告: 无法获取 GitHub Token，不统计收件箱深度")
+		return nil
+	}
+	svc := service.NewIssueServiceWithConfig(serviceConfig(token)).WithTracer(activeMetrics.observeGitHub)
+	go activeMetrics.refreshInbox(context.Background(), svc, repos, serveMetricsInterval)
+	return nil
+}
+
+// runStdioServer 通过 stdin/stdout 处理 JSON-RPC 消息，请求并发处理，响应串行写出
+func runStdioServer() error {
 	scanner := bufio.NewScanner(os.Stdin)
 	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
 
+	writer := &mcpWriter{w: os.Stdout}
+	dispatcher := newMCPDispatcher(writer.write)
+	reply := func(response *jsonRPCResponse) {
+		if response != nil {
+			writer.write(response)
+		}
+	}
+
 	for scanner.Scan() {
 		line := scanner.Text()
 		if line == "" {
@@ -117,30 +225,43 @@ func runServe(cmd *cobra.Command, args []string) error {
 
 		var request jsonRPCRequest
 		if err := json.Unmarshal([]byte(line), &request); err != nil {
-			sendError(nil, -32700, "Parse error", err.Error())
+			dispatcher.log("error", "transport", "解析 JSON-RPC 消息失败", logFields{"error": err.Error()})
+			writer.write(rpcErrorResponse(nil, -32700, "Parse error", err.Error()))
 			continue
 		}
 
-		response := handleMCPRequest(&request)
-		if response != nil {
-			sendMCPResponse(response)
-		}
+		dispatcher.dispatch(context.Background(), &request, reply)
 	}
 
+	dispatcher.wait()
 	return scanner.Err()
 }
 
-func handleMCPRequest(request *jsonRPCRequest) *jsonRPCResponse {
+func handleMCPRequest(ctx context.Context, request *jsonRPCRequest) *jsonRPCResponse {
 	switch request.Method {
 	case "initialize":
 		return handleMCPInitialize(request)
-	case "initialized":
+	case "initialized", "notifications/initialized":
 		return nil
 	case "tools/list":
 		return handleMCPToolsList(request)
 	case "tools/call":
-		return handleMCPToolsCall(request)
+		return handleMCPToolsCall(ctx, request)
+	case "resources/list":
+		return handleMCPResourcesList(ctx, request)
+	case "resources/templates/list":
+		return handleMCPResourceTemplatesList(request)
+	case "resources/read":
+		return handleMCPResourcesRead(ctx, request)
+	case "prompts/list":
+		return handleMCPPromptsList(request)
+	case "prompts/get":
+		return handleMCPPromptsGet(ctx, request)
 	default:
+		// 通知没有 id，不需要响应
+		if request.ID == nil {
+			return nil
+		}
 		return &jsonRPCResponse{
 			JSONRPC: "2.0",
 			ID:      request.ID,
@@ -153,13 +274,35 @@ func handleMCPRequest(request *jsonRPCRequest) *jsonRPCResponse {
 	}
 }
 
+// supportedProtocolVersions 支持的 MCP 协议版本，第一个为最新版本
+var supportedProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// negotiateProtocolVersion 客户端请求的版本受支持时沿用，否则返回最新版本
+func negotiateProtocolVersion(params json.RawMessage) string {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		json.Unmarshal(params, &p)
+	}
+	for _, v := range supportedProtocolVersions {
+		if v == p.ProtocolVersion {
+			return v
+		}
+	}
+	return supportedProtocolVersions[0]
+}
+
 func handleMCPInitialize(request *jsonRPCRequest) *jsonRPCResponse {
 	result := initializeResult{
-		ProtocolVersion: "2024-11-05",
+		ProtocolVersion: negotiateProtocolVersion(request.Params),
 		Capabilities: capabilities{
 			Tools: &toolsCapability{
 				ListChanged: false,
 			},
+			Resources: &resourcesCapability{},
+			Prompts:   &promptsCapability{},
+			Logging:   &loggingCapability{},
 		},
 		ServerInfo: serverInfo{
 			Name:    "github-issue",
@@ -175,134 +318,9 @@ func handleMCPInitialize(request *jsonRPCRequest) *jsonRPCResponse {
 }
 
 func handleMCPToolsList(request *jsonRPCRequest) *jsonRPCResponse {
-	tools := []tool{
-		{
-			Name:        "github_issue_create",
-			Description: "创建标准化的 GitHub Issue，自动打包内容到 Gist",
-			InputSchema: inputSchema{
-				Type: "object",
-				Properties: map[string]property{
-					"repo": {
-						Type:        "string",
-						Description: "目标仓库 (格式: owner/repo)",
-					},
-					"type": {
-						Type:        "string",
-						Description: "Issue 类型",
-						Enum:        []string{"feature-request", "bug-report", "pack-register", "pack-sync", "question", "custom"},
-					},
-					"title": {
-						Type:        "string",
-						Description: "Issue 标题",
-					},
-					"payload": {
-						Type:        "string",
-						Description: "详细内容 (JSON 字符串)",
-					},
-				},
-				Required: []string{"repo", "type", "title"},
-			},
-		},
-		{
-			Name:        "github_issue_list",
-			Description: "列出仓库中的标准化 Issue",
-			InputSchema: inputSchema{
-				Type: "object",
-				Properties: map[string]property{
-					"repo": {
-						Type:        "string",
-						Description: "目标仓库 (格式: owner/repo)",
-					},
-					"status": {
-						Type:        "string",
-						Description: "状态过滤",
-						Enum:        []string{"pending", "processing", "processed", "all"},
-					},
-					"type": {
-						Type:        "string",
-						Description: "类型过滤",
-						Enum:        []string{"feature-request", "bug-report", "pack-register", "pack-sync"},
-					},
-					"limit": {
-						Type:        "string",
-						Description: "返回数量限制 (默认 20)",
-					},
-				},
-				Required: []string{"repo"},
-			},
-		},
-		{
-			Name:        "github_issue_get",
-			Description: "获取 Issue 详情，包括解析的 payload",
-			InputSchema: inputSchema{
-				Type: "object",
-				Properties: map[string]property{
-					"repo": {
-						Type:        "string",
-						Description: "目标仓库 (格式: owner/repo)",
-					},
-					"number": {
-						Type:        "string",
-						Description: "Issue 编号",
-					},
-				},
-				Required: []string{"repo", "number"},
-			},
-		},
-		{
-			Name:        "github_issue_update",
-			Description: "更新 Issue 状态",
-			InputSchema: inputSchema{
-				Type: "object",
-				Properties: map[string]property{
-					"repo": {
-						Type:        "string",
-						Description: "目标仓库 (格式: owner/repo)",
-					},
-					"number": {
-						Type:        "string",
-						Description: "Issue 编号",
-					},
-					"status": {
-						Type:        "string",
-						Description: "新状态",
-						Enum:        []string{"pending", "processing"},
-					},
-					"comment": {
-						Type:        "string",
-						Description: "添加评论 (可选)",
-					},
-				},
-				Required: []string{"repo", "number", "status"},
-			},
-		},
-		{
-			Name:        "github_issue_close",
-			Description: "关闭 Issue",
-			InputSchema: inputSchema{
-				Type: "object",
-				Properties: map[string]property{
-					"repo": {
-						Type:        "string",
-						Description: "目标仓库 (格式: owner/repo)",
-					},
-					"number": {
-						Type:        "string",
-						Description: "Issue 编号",
-					},
-					"result": {
-						Type:        "string",
-						Description: "处理结果",
-						Enum:        []string{"success", "rejected"},
-					},
-					"comment": {
-						Type:        "string",
-						Description: "关闭说明 (可选)",
-					},
-				},
-				Required: []string{"repo", "number", "result"},
-			},
-		},
+	tools := make([]tool, 0, len(operations))
+	for _, op := range operations {
+		tools = append(tools, op.tool())
 	}
 
 	return &jsonRPCResponse{
@@ -312,7 +330,7 @@ func handleMCPToolsList(request *jsonRPCRequest) *jsonRPCResponse {
 	}
 }
 
-func handleMCPToolsCall(request *jsonRPCRequest) *jsonRPCResponse {
+func handleMCPToolsCall(ctx context.Context, request *jsonRPCRequest) *jsonRPCResponse {
 	var params callToolParams
 	if err := json.Unmarshal(request.Params, &params); err != nil {
 		return &jsonRPCResponse{
@@ -327,23 +345,13 @@ func handleMCPToolsCall(request *jsonRPCRequest) *jsonRPCResponse {
 	}
 
 	var result callToolResult
-
-	switch params.Name {
-	case "github_issue_create":
-		result = executeCreate(params.Arguments)
-	case "github_issue_list":
-		result = executeList(params.Arguments)
-	case "github_issue_get":
-		result = executeGet(params.Arguments)
-	case "github_issue_update":
-		result = executeUpdate(params.Arguments)
-	case "github_issue_close":
-		result = executeClose(params.Arguments)
-	default:
-		result = callToolResult{
-			Content: []contentItem{{Type: "text", Text: fmt.Sprintf("未知的工具: %s", params.Name)}},
-			IsError: true,
-		}
+	if op := findOperation(strings.TrimPrefix(params.Name, "github_issue_")); op != nil && strings.HasPrefix(params.Name, "github_issue_") {
+		result = op.call(ctx, params.Arguments)
+		activeMetrics.observeToolCall(op.Name, result.IsError)
+	} else {
+		result = toolError("未知的工具: %s", params.Name)
+		// 未知工具名由客户端决定，不作为标签值以免指标无限增长
+		activeMetrics.observeToolCall("unknown", true)
 	}
 
 	return &jsonRPCResponse{
@@ -354,13 +362,19 @@ func handleMCPToolsCall(request *jsonRPCRequest) *jsonRPCResponse {
 }
 
 func getMCPToken() string {
-	// 1. 环境变量
-	token := os.Getenv("GITHUB_TOKEN")
+	// 1. profile 配置的 token 来源
+	token, _ := activeProfile.ResolveToken(getGhToken)
+	if token != "" {
+		return token
+	}
+
+	// 2. 环境变量
+	token = os.Getenv("GITHUB_TOKEN")
 	if token != "" {
 		return token
 	}
 
-	// 2. 从 gh CLI 获取
+	// 3. 从 gh CLI 获取
 	token = getGhToken()
 	if token != "" {
 		return token
@@ -369,295 +383,92 @@ func getMCPToken() string {
 	return ""
 }
 
-func executeCreate(args map[string]interface{}) callToolResult {
-	repo, _ := args["repo"].(string)
-	issueType, _ := args["type"].(string)
-	title, _ := args["title"].(string)
-	payloadStr, _ := args["payload"].(string)
-
-	if repo == "" || issueType == "" || title == "" {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "缺少必需参数: repo, type, title"}},
-			IsError: true,
-		}
-	}
-
-	token := getMCPToken()
-	if token == "" {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "无法获取 GitHub Token，请设置 GITHUB_TOKEN 环境变量或运行 gh auth login"}},
-			IsError: true,
-		}
-	}
-
-	// 解析 payload
-	var payload interface{}
-	if payloadStr != "" {
-		if err := json.Unmarshal([]byte(payloadStr), &payload); err != nil {
-			return callToolResult{
-				Content: []contentItem{{Type: "text", Text: fmt.Sprintf("解析 payload 失败: %v", err)}},
-				IsError: true,
-			}
-		}
-	} else {
-		payload = map[string]string{
-			"title":       title,
-			"description": "",
-		}
-	}
-
-	svc := service.NewIssueService(token)
-	result, err := svc.Create(service.CreateIssueOptions{
-		Repo:    repo,
-		Type:    models.IssueType(issueType),
-		Title:   title,
-		Payload: payload,
-		DryRun:  false,
-	})
-	if err != nil {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: fmt.Sprintf("创建 Issue 失败: %v", err)}},
-			IsError: true,
-		}
-	}
-
-	return callToolResult{
-		Content: []contentItem{{
-			Type: "text",
-			Text: fmt.Sprintf("✅ Issue 创建成功!\n\nIssue: %s\nGist: %s", result.IssueURL, result.GistURL),
-		}},
-	}
+// newMCPIssueService 创建使用当前 profile 的 IssueService，请求取消时中断 GitHub 调用，
+// 客户端提供 progressToken 时报告进度，GitHub API 调用记录为 debug 日志
+func newMCPIssueService(ctx context.Context, token string) *service.IssueService {
+	return service.NewIssueServiceWithConfig(serviceConfig(token)).
+		WithContext(ctx).
+		WithProgress(mcpProgress(ctx)).
+		WithTracer(mcpTracer(ctx)).
+		WithWarnings(func(message string) {
+			mcpLog(ctx, "warning", "github", message, nil)
+		})
 }
 
-func executeList(args map[string]interface{}) callToolResult {
+// mcpRepo 返回工具参数中的仓库，未指定时使用 profile 的默认仓库
+func mcpRepo(args map[string]interface{}) string {
 	repo, _ := args["repo"].(string)
-	status, _ := args["status"].(string)
-	issueType, _ := args["type"].(string)
-	limitStr, _ := args["limit"].(string)
-
 	if repo == "" {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "缺少必需参数: repo"}},
-			IsError: true,
-		}
-	}
-
-	token := getMCPToken()
-	if token == "" {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "无法获取 GitHub Token"}},
-			IsError: true,
-		}
-	}
-
-	limit := 20
-	if limitStr != "" {
-		fmt.Sscanf(limitStr, "%d", &limit)
-	}
-
-	svc := service.NewIssueService(token)
-	issues, err := svc.List(service.ListOptions{
-		Repo:   repo,
-		Status: status,
-		Type:   issueType,
-		Limit:  limit,
-	})
-	if err != nil {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: fmt.Sprintf("列出 Issue 失败: %v", err)}},
-			IsError: true,
-		}
-	}
-
-	if len(issues) == 0 {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "没有找到匹配的 Issue"}},
-		}
-	}
-
-	var text string
-	text = fmt.Sprintf("找到 %d 个 Issue:\n\n", len(issues))
-	for _, issue := range issues {
-		text += fmt.Sprintf("- #%d [%s] %s (%s)\n  %s\n", issue.Number, issue.Status, issue.Title, issue.Type, issue.URL)
-	}
-
-	return callToolResult{
-		Content: []contentItem{{Type: "text", Text: text}},
+		repo = activeProfile.Repo
 	}
+	return repo
 }
 
-func executeGet(args map[string]interface{}) callToolResult {
-	repo, _ := args["repo"].(string)
-	numberStr, _ := args["number"].(string)
-
-	if repo == "" || numberStr == "" {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "缺少必需参数: repo, number"}},
-			IsError: true,
+// intArg 读取整数参数，兼容旧版 schema 下客户端传入的字符串；参数不存在时返回 0
+func intArg(args map[string]interface{}, name string) (int, error) {
+	switch v := args[name].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case float64:
+		if v == math.Trunc(v) {
+			return int(v), nil
 		}
-	}
-
-	var number int
-	fmt.Sscanf(numberStr, "%d", &number)
-	if number <= 0 {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "无效的 Issue 编号"}},
-			IsError: true,
+	case string:
+		if v == "" {
+			return 0, nil
 		}
-	}
-
-	token := getMCPToken()
-	if token == "" {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "无法获取 GitHub Token"}},
-			IsError: true,
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return n, nil
 		}
 	}
-
-	svc := service.NewIssueService(token)
-	result, err := svc.Get(repo, number)
-	if err != nil {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: fmt.Sprintf("获取 Issue 失败: %v", err)}},
-			IsError: true,
-		}
-	}
-
-	var text string
-	text = fmt.Sprintf("Issue #%d: %s\n\n", result.Issue.Number, result.Issue.Title)
-	text += fmt.Sprintf("状态: %s\n", result.Issue.State)
-	text += fmt.Sprintf("创建时间: %s\n", result.Issue.CreatedAt)
-	text += fmt.Sprintf("URL: %s\n", result.Issue.HTMLURL)
-
-	if result.Package != nil {
-		text += fmt.Sprintf("\n类型: %s\n", result.Package.Type)
-		if result.Package.Payload != nil {
-			payloadJSON, _ := json.MarshalIndent(result.Package.Payload, "", "  ")
-			text += fmt.Sprintf("\nPayload:\n%s\n", string(payloadJSON))
-		}
-	}
-
-	return callToolResult{
-		Content: []contentItem{{Type: "text", Text: text}},
-	}
+	return 0, fmt.Errorf("参数 %s 必须是整数", name)
 }
 
-func executeUpdate(args map[string]interface{}) callToolResult {
-	repo, _ := args["repo"].(string)
-	numberStr, _ := args["number"].(string)
-	status, _ := args["status"].(string)
-	comment, _ := args["comment"].(string)
-
-	if repo == "" || numberStr == "" || status == "" {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "缺少必需参数: repo, number, status"}},
-			IsError: true,
-		}
-	}
-
-	var number int
-	fmt.Sscanf(numberStr, "%d", &number)
-	if number <= 0 {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "无效的 Issue 编号"}},
-			IsError: true,
-		}
-	}
-
-	token := getMCPToken()
-	if token == "" {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "无法获取 GitHub Token"}},
-			IsError: true,
+// objectArg 读取 JSON 对象参数，兼容以 JSON 字符串传入；参数不存在时返回 nil
+func objectArg(args map[string]interface{}, name string) (interface{}, error) {
+	switch v := args[name].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
 		}
-	}
-
-	svc := service.NewIssueService(token)
-	err := svc.UpdateStatus(repo, number, status, comment)
-	if err != nil {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: fmt.Sprintf("更新 Issue 失败: %v", err)}},
-			IsError: true,
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(v), &parsed); err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %v", name, err)
 		}
+		return parsed, nil
+	default:
+		return v, nil
 	}
+}
 
+// toolError 返回工具执行失败的结果
+func toolError(format string, a ...interface{}) callToolResult {
 	return callToolResult{
-		Content: []contentItem{{
-			Type: "text",
-			Text: fmt.Sprintf("✅ Issue #%d 状态已更新为 %s", number, status),
-		}},
+		Content: []contentItem{{Type: "text", Text: fmt.Sprintf(format, a...)}},
+		IsError: true,
 	}
 }
 
-func executeClose(args map[string]interface{}) callToolResult {
-	repo, _ := args["repo"].(string)
-	numberStr, _ := args["number"].(string)
-	result, _ := args["result"].(string)
-	comment, _ := args["comment"].(string)
-
-	if repo == "" || numberStr == "" || result == "" {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "缺少必需参数: repo, number, result"}},
-			IsError: true,
-		}
-	}
-
-	var number int
-	fmt.Sscanf(numberStr, "%d", &number)
-	if number <= 0 {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "无效的 Issue 编号"}},
-			IsError: true,
-		}
-	}
-
-	token := getMCPToken()
-	if token == "" {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: "无法获取 GitHub Token"}},
-			IsError: true,
-		}
-	}
-
-	svc := service.NewIssueService(token)
-	err := svc.Close(repo, number, result, comment)
-	if err != nil {
-		return callToolResult{
-			Content: []contentItem{{Type: "text", Text: fmt.Sprintf("关闭 Issue 失败: %v", err)}},
-			IsError: true,
-		}
-	}
-
-	statusText := "成功"
-	if result == "rejected" {
-		statusText = "已拒绝"
-	}
-
+// toolResult 返回工具结果：text 供人阅读，structured 为符合 outputSchema 的结构化结果
+func toolResult(text string, structured interface{}) callToolResult {
 	return callToolResult{
-		Content: []contentItem{{
-			Type: "text",
-			Text: fmt.Sprintf("✅ Issue #%d 已关闭 (%s)", number, statusText),
-		}},
+		Content:           []contentItem{{Type: "text", Text: text}},
+		StructuredContent: structured,
 	}
 }
 
-func sendMCPResponse(response *jsonRPCResponse) {
-	data, err := json.Marshal(response)
+// issueNumberArg 读取并校验 number 参数
+func issueNumberArg(args map[string]interface{}) (int, error) {
+	number, err := intArg(args, "number")
 	if err != nil {
-		return
+		return 0, err
 	}
-	fmt.Println(string(data))
-}
-
-func sendError(id interface{}, code int, message string, data interface{}) {
-	response := &jsonRPCResponse{
-		JSONRPC: "2.0",
-		ID:      id,
-		Error: &rpcError{
-			Code:    code,
-			Message: message,
-			Data:    data,
-		},
+	if number <= 0 {
+		return 0, fmt.Errorf("无效的 Issue 编号")
 	}
-	sendMCPResponse(response)
+	return number, nil
 }
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+)
+
+// jsonRPCNotification 服务端发出的通知（没有 id，不需要响应）
+type jsonRPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type cancelledParams struct {
+	RequestID interface{} `json:"requestId"`
+	Reason    string      `json:"reason,omitempty"`
+}
+
+type progressParams struct {
+	ProgressToken interface{} `json:"progressToken"`
+	Progress      float64     `json:"progress"`
+	Total         float64     `json:"total,omitempty"`
+	Message       string      `json:"message,omitempty"`
+}
+
+// mcpWriter 串行化写出消息，保证并发处理的响应不会在 stdout 上交错
+type mcpWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *mcpWriter) write(message interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintln(w.w, string(data))
+}
+
+// mcpDispatcher 并发处理一个连接（或 HTTP 会话）上的请求
+//
+// 每个请求在独立的 goroutine 中处理，并持有可被 notifications/cancelled
+// 取消的 context；send 用于向客户端推送进度等通知。
+type mcpDispatcher struct {
+	send func(message interface{})
+
+	mu       sync.Mutex
+	inflight map[string]context.CancelFunc
+	logLevel string // 客户端通过 logging/setLevel 设置的最低推送级别
+	wg       sync.WaitGroup
+}
+
+func newMCPDispatcher(send func(message interface{})) *mcpDispatcher {
+	return &mcpDispatcher{
+		send:     send,
+		inflight: make(map[string]context.CancelFunc),
+		logLevel: defaultClientLogLevel,
+	}
+}
+
+// dispatch 处理一条消息，reply 恰好被调用一次，不需要响应时参数为 nil
+//
+// ping 和 notifications/cancelled 同步处理，其余请求异步处理。
+func (d *mcpDispatcher) dispatch(parent context.Context, request *jsonRPCRequest, reply func(*jsonRPCResponse)) {
+	switch request.Method {
+	case "ping":
+		reply(&jsonRPCResponse{JSONRPC: "2.0", ID: request.ID, Result: struct{}{}})
+		return
+	case "notifications/cancelled":
+		var params cancelledParams
+		if err := json.Unmarshal(request.Params, &params); err == nil {
+			d.cancel(params.RequestID)
+		}
+		reply(nil)
+		return
+	case "logging/setLevel":
+		reply(d.setLogLevel(request))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	key := requestKey(request.ID)
+	if request.ID != nil {
+		d.mu.Lock()
+		d.inflight[key] = cancel
+		d.mu.Unlock()
+	}
+	// POST 请求以 SSE 响应时，请求的通知写入该请求自己的流
+	send := d.send
+	if stream, ok := parent.Value(mcpStreamKey{}).(func(message interface{})); ok {
+		send = stream
+	}
+	ctx = context.WithValue(ctx, mcpCallKey{}, &mcpCall{
+		dispatcher:    d,
+		send:          send,
+		progressToken: progressToken(request.Params),
+	})
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		start := time.Now()
+		response := handleMCPRequest(ctx, request)
+		d.logRequest(request, response, time.Since(start), ctx.Err() != nil)
+
+		if request.ID != nil {
+			d.mu.Lock()
+			delete(d.inflight, key)
+			d.mu.Unlock()
+		}
+		// 已取消的请求不再发送响应
+		if ctx.Err() != nil {
+			response = nil
+		}
+		cancel()
+		reply(response)
+	}()
+}
+
+// logRequest 记录请求的处理耗时和结果
+func (d *mcpDispatcher) logRequest(request *jsonRPCRequest, response *jsonRPCResponse, elapsed time.Duration, cancelled bool) {
+	fields := logFields{
+		"method":      request.Method,
+		"duration_ms": elapsed.Milliseconds(),
+	}
+	if request.ID != nil {
+		fields["id"] = request.ID
+	}
+	if request.Method == "tools/call" {
+		var params callToolParams
+		if json.Unmarshal(request.Params, &params) == nil {
+			fields["tool"] = params.Name
+		}
+	}
+	if cancelled {
+		fields["cancelled"] = true
+	}
+
+	level := "info"
+	if response != nil && response.Error != nil {
+		fields["error"] = response.Error.Message
+		level = "warning"
+	}
+	d.log(level, "server", "处理请求", fields)
+}
+
+// cancel 取消进行中的请求，请求不存在（已完成或 id 无效）时忽略
+func (d *mcpDispatcher) cancel(id interface{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if cancel, ok := d.inflight[requestKey(id)]; ok {
+		cancel()
+		delete(d.inflight, requestKey(id))
+	}
+}
+
+// wait 等待所有进行中的请求处理完毕
+func (d *mcpDispatcher) wait() {
+	d.wg.Wait()
+}
+
+// requestKey 将请求 id 规范化为 map key，区分数字 1 和字符串 "1"
+func requestKey(id interface{}) string {
+	data, _ := json.Marshal(id)
+	return string(data)
+}
+
+// progressToken 读取请求 params._meta.progressToken，未提供时返回 nil
+func progressToken(params json.RawMessage) interface{} {
+	var p struct {
+		Meta struct {
+			ProgressToken interface{} `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if len(params) > 0 {
+		json.Unmarshal(params, &p)
+	}
+	return p.Meta.ProgressToken
+}
+
+// mcpCall 单个请求的处理上下文
+type mcpCall struct {
+	dispatcher    *mcpDispatcher
+	send          func(message interface{}) // 发送该请求的进度和日志通知
+	progressToken interface{}
+}
+
+type mcpCallKey struct{}
+
+// mcpStreamKey context 中 POST 请求 SSE 流的发送函数
+type mcpStreamKey struct{}
+
+// mcpProgress 返回向客户端发送 notifications/progress 的回调，客户端未请求进度时返回 nil
+func mcpProgress(ctx context.Context) service.ProgressFunc {
+	call, _ := ctx.Value(mcpCallKey{}).(*mcpCall)
+	if call == nil || call.progressToken == nil {
+		return nil
+	}
+	return func(progress, total float64, message string) {
+		if ctx.Err() != nil {
+			return
+		}
+		call.send(&jsonRPCNotification{
+			JSONRPC: "2.0",
+			Method:  "notifications/progress",
+			Params: progressParams{
+				ProgressToken: call.progressToken,
+				Progress:      progress,
+				Total:         total,
+				Message:       message,
+			},
+		})
+	}
+}
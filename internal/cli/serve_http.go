@@ -0,0 +1,425 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// mcpAuthTokenEnv HTTP 传输的 Bearer Token 环境变量
+	mcpAuthTokenEnv = "GITHUB_ISSUE_MCP_AUTH_TOKEN"
+
+	// mcpSessionHeader MCP 会话 ID 请求/响应头
+	mcpSessionHeader = "Mcp-Session-Id"
+
+	// mcpEndpoint MCP Streamable HTTP 端点路径
+	mcpEndpoint = "/mcp"
+
+	mcpMaxBodySize        = 10 * 1024 * 1024
+	mcpSessionIdleTimeout = 24 * time.Hour
+	mcpSSEKeepAlive       = 25 * time.Second
+
+	// HTTP 超时：防止慢速客户端占用连接；GET SSE 流不受读写超时限制
+	mcpReadHeaderTimeout = 10 * time.Second
+	mcpReadTimeout       = 60 * time.Second
+	mcpWriteTimeout      = 10 * time.Minute
+)
+
+// mcpSession HTTP 传输的客户端会话
+type mcpSession struct {
+	id         string
+	lastSeen   time.Time
+	events     chan []byte // 通过 GET SSE 流推送给客户端的消息
+	dispatcher *mcpDispatcher
+}
+
+// notify 向会话的 SSE 流推送消息，流未打开或已满时丢弃
+func (s *mcpSession) notify(message interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	select {
+	case s.events <- data:
+	default:
+	}
+}
+
+// mcpHTTPServer MCP Streamable HTTP 传输
+type mcpHTTPServer struct {
+	authToken string
+
+	mu       sync.Mutex
+	sessions map[string]*mcpSession
+}
+
+func runHTTPServer(listen, authToken string, insecureNoAuth bool) error {
+	if authToken == "" && !isLoopbackAddr(listen) {
+		if !insecureNoAuth {
+			return fmt.Errorf("在非本机地址 %s 上监听时必须设置 --auth-token（或 %s 环境变量），否则任何人都可以使用本 Server 的 GitHub Token；确需关闭认证请使用 --insecure-no-auth", listen, mcpAuthTokenEnv)
+		}
+		fmt.Fprintf(os.Stderr, "警告: 在非本机地址 %s 上监听且未设置 --auth-token，任何人都可以使用本 Server 的 GitHub Token\n", listen)
+	}
+
+	srv := &mcpHTTPServer{
+		authToken: authToken,
+		sessions:  make(map[string]*mcpSession),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(mcpEndpoint, srv)
+
+	server := &http.Server{
+		Addr:              listen,
+		Handler:           mux,
+		ReadHeaderTimeout: mcpReadHeaderTimeout,
+		ReadTimeout:       mcpReadTimeout,
+		WriteTimeout:      mcpWriteTimeout,
+	}
+	fmt.Fprintf(os.Stderr, "MCP Server 已启动: http://%s%s\n", listen, mcpEndpoint)
+	return server.ListenAndServe()
+}
+
+// isLoopbackAddr 判断监听地址是否只绑定本机
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *mcpHTTPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		serveLog.log("warning", "transport", "认证失败", logFields{"remote": r.RemoteAddr})
+		w.Header().Set("WWW-Authenticate", `Bearer realm="github-issue"`)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !validOrigin(r) {
+		http.Error(w, "forbidden origin", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		s.handlePost(w, r)
+	case http.MethodGet:
+		s.handleGet(w, r)
+	case http.MethodDelete:
+		s.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authorized 校验 Bearer Token，未配置 token 时不校验
+func (s *mcpHTTPServer) authorized(r *http.Request) bool {
+	if s.authToken == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.authToken)) == 1
+}
+
+// validOrigin 防止 DNS rebinding：浏览器请求的 Origin 必须与 Host 一致
+func validOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+func (s *mcpHTTPServer) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, mcpMaxBodySize))
+	if err != nil {
+		http.Error(w, "read body failed", http.StatusBadRequest)
+		return
+	}
+	// 请求体已读完，工具调用耗时可能超过 ReadTimeout，取消读超时以免连接被判定超时而取消请求
+	http.NewResponseController(w).SetReadDeadline(time.Time{})
+
+	// 支持单条消息和批量消息
+	batch := bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+	var requests []jsonRPCRequest
+	if batch {
+		err = json.Unmarshal(body, &requests)
+	} else {
+		var request jsonRPCRequest
+		err = json.Unmarshal(body, &request)
+		requests = []jsonRPCRequest{request}
+	}
+	if err != nil {
+		serveLog.log("error", "transport", "解析 JSON-RPC 消息失败", logFields{"error": err.Error(), "remote": r.RemoteAddr})
+		writeJSON(w, http.StatusBadRequest, &jsonRPCResponse{
+			JSONRPC: "2.0",
+			Error:   &rpcError{Code: -32700, Message: "Parse error", Data: err.Error()},
+		})
+		return
+	}
+
+	// initialize 创建新会话，其他消息必须携带有效的会话 ID
+	var session *mcpSession
+	if len(requests) == 1 && requests[0].Method == "initialize" {
+		session = s.newSession()
+		w.Header().Set(mcpSessionHeader, session.id)
+		serveLog.log("info", "transport", "创建会话", logFields{"session": session.id, "remote": r.RemoteAddr})
+	} else {
+		id := r.Header.Get(mcpSessionHeader)
+		if id == "" {
+			http.Error(w, "missing "+mcpSessionHeader, http.StatusBadRequest)
+			return
+		}
+		if session = s.session(id); session == nil {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	if acceptsSSE(r) && hasRequest(requests) {
+		s.streamPost(w, r, session, requests)
+		return
+	}
+
+	// 批量消息并发处理，响应保持请求顺序；进度等通知通过 GET SSE 流推送
+	slots := make([]*jsonRPCResponse, len(requests))
+	var wg sync.WaitGroup
+	for i := range requests {
+		if requests[i].Method == "" {
+			// 客户端对服务端请求的响应，本 Server 不发起请求，忽略
+			continue
+		}
+		i := i
+		wg.Add(1)
+		session.dispatcher.dispatch(r.Context(), &requests[i], func(response *jsonRPCResponse) {
+			slots[i] = response
+			wg.Done()
+		})
+	}
+	wg.Wait()
+
+	var responses []*jsonRPCResponse
+	for _, response := range slots {
+		if response != nil {
+			responses = append(responses, response)
+		}
+	}
+
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	if batch {
+		writeJSON(w, http.StatusOK, responses)
+		return
+	}
+	writeJSON(w, http.StatusOK, responses[0])
+}
+
+// acceptsSSE 客户端是否接受以 SSE 流返回 POST 的结果
+func acceptsSSE(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// hasRequest 消息中是否有需要响应的请求（有 id）
+func hasRequest(requests []jsonRPCRequest) bool {
+	for _, request := range requests {
+		if request.Method != "" && request.ID != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// sseStream 一个 SSE 响应流，可被多个请求的处理 goroutine 并发写入
+type sseStream struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	closed  bool
+}
+
+// send 写出一条消息，流结束后写入的消息被丢弃
+func (st *sseStream) send(message interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.closed {
+		return
+	}
+	fmt.Fprintf(st.w, "event: message\ndata: %s\n\n", data)
+	st.flusher.Flush()
+}
+
+func (st *sseStream) close() {
+	st.mu.Lock()
+	st.closed = true
+	st.mu.Unlock()
+}
+
+// streamPost 以 SSE 流返回 POST 的结果：请求处理过程中的进度和日志通知
+// 以及每个请求的响应依次写入该流，全部响应写出后结束
+func (s *mcpHTTPServer) streamPost(w http.ResponseWriter, r *http.Request, session *mcpSession, requests []jsonRPCRequest) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	stream := &sseStream{w: w, flusher: flusher}
+	defer stream.close()
+	ctx := context.WithValue(r.Context(), mcpStreamKey{}, stream.send)
+
+	var wg sync.WaitGroup
+	for i := range requests {
+		if requests[i].Method == "" {
+			continue
+		}
+		wg.Add(1)
+		session.dispatcher.dispatch(ctx, &requests[i], func(response *jsonRPCResponse) {
+			if response != nil {
+				stream.send(response)
+			}
+			wg.Done()
+		})
+	}
+	wg.Wait()
+}
+
+// handleGet 打开 SSE 流，用于服务端主动推送消息
+func (s *mcpHTTPServer) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		http.Error(w, "Accept must include text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+	session := s.session(r.Header.Get(mcpSessionHeader))
+	if session == nil {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// 长连接不受服务器的读写超时限制
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(mcpSSEKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data := <-session.events:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			flusher.Flush()
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+			// 只保持 SSE 流的客户端也在活动，避免会话被当作空闲会话清理
+			s.touch(session)
+		}
+	}
+}
+
+// handleDelete 客户端主动结束会话
+func (s *mcpHTTPServer) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(mcpSessionHeader)
+	s.mu.Lock()
+	_, ok := s.sessions[id]
+	delete(s.sessions, id)
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+	serveLog.log("info", "transport", "结束会话", logFields{"session": id})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// newSession 创建会话，并顺带清理长时间未活动的会话
+func (s *mcpHTTPServer) newSession() *mcpSession {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	session := &mcpSession{
+		id:       hex.EncodeToString(buf),
+		lastSeen: time.Now(),
+		events:   make(chan []byte, 64),
+	}
+	session.dispatcher = newMCPDispatcher(session.notify)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, sess := range s.sessions {
+		if time.Since(sess.lastSeen) > mcpSessionIdleTimeout {
+			delete(s.sessions, id)
+		}
+	}
+	s.sessions[session.id] = session
+	return session
+}
+
+// session 查找会话并刷新活动时间
+func (s *mcpHTTPServer) session(id string) *mcpSession {
+	if id == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session := s.sessions[id]
+	if session != nil {
+		session.lastSeen = time.Now()
+	}
+	return session
+}
+
+// touch 刷新会话的活动时间
+func (s *mcpHTTPServer) touch(session *mcpSession) {
+	s.mu.Lock()
+	session.lastSeen = time.Now()
+	s.mu.Unlock()
+}
+
+// writeJSON 写入 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+)
+
+// mcpLogLevels MCP 日志级别（RFC 5424），按严重程度递增
+var mcpLogLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+// defaultClientLogLevel 客户端未调用 logging/setLevel 时推送的最低级别
+const defaultClientLogLevel = "warning"
+
+// MCP 日志相关消息
+type loggingCapability struct{}
+
+type setLevelParams struct {
+	Level string `json:"level"`
+}
+
+type logMessageParams struct {
+	Level  string      `json:"level"`
+	Logger string      `json:"logger,omitempty"`
+	Data   interface{} `json:"data"`
+}
+
+// logLevelIndex 返回级别的严重程度，无效级别返回 -1
+func logLevelIndex(level string) int {
+	for i, l := range mcpLogLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// logFields 日志的附加字段
+type logFields map[string]interface{}
+
+// serverLogger 将诊断日志以 JSON Lines 写入 --log-file
+type serverLogger struct {
+	mu       sync.Mutex
+	w        io.Writer
+	minLevel int
+}
+
+// serveLog serve 的日志文件，未指定 --log-file 时为 nil
+var serveLog *serverLogger
+
+// openServerLog 打开日志文件（追加写入）
+func openServerLog(path, level string) (*serverLogger, error) {
+	minLevel := logLevelIndex(level)
+	if minLevel < 0 {
+		return nil, fmt.Errorf("无效的日志级别: %s", level)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("打开日志文件失败: %w", err)
+	}
+	return &serverLogger{w: f, minLevel: minLevel}, nil
+}
+
+func (l *serverLogger) log(level, logger, message string, fields logFields) {
+	if l == nil || logLevelIndex(level) < l.minLevel {
+		return
+	}
+
+	entry := logFields{
+		"time":   time.Now().Format(time.RFC3339Nano),
+		"level":  level,
+		"logger": logger,
+		"msg":    message,
+	}
+	for k, v := range fields {
+		entry[k] = v
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintln(l.w, string(data))
+}
+
+// log 写入日志文件，并在达到客户端设置的级别时发送 notifications/message
+func (d *mcpDispatcher) log(level, logger, message string, fields logFields) {
+	d.logTo(d.send, level, logger, message, fields)
+}
+
+// logTo 同 log，notifications/message 通过 send 发送（如 POST 请求自己的 SSE 流）
+func (d *mcpDispatcher) logTo(send func(message interface{}), level, logger, message string, fields logFields) {
+	serveLog.log(level, logger, message, fields)
+
+	d.mu.Lock()
+	clientLevel := d.logLevel
+	d.mu.Unlock()
+	if logLevelIndex(level) < logLevelIndex(clientLevel) {
+		return
+	}
+
+	data := logFields{"message": message}
+	for k, v := range fields {
+		data[k] = v
+	}
+	send(&jsonRPCNotification{
+		JSONRPC: "2.0",
+		Method:  "notifications/message",
+		Params:  logMessageParams{Level: level, Logger: logger, Data: data},
+	})
+}
+
+// setLogLevel 处理 logging/setLevel
+func (d *mcpDispatcher) setLogLevel(request *jsonRPCRequest) *jsonRPCResponse {
+	var params setLevelParams
+	if err := json.Unmarshal(request.Params, &params); err != nil {
+		return rpcErrorResponse(request.ID, -32602, "Invalid params", err.Error())
+	}
+	if logLevelIndex(params.Level) < 0 {
+		return rpcErrorResponse(request.ID, -32602, "Invalid params", fmt.Sprintf("无效的日志级别: %s", params.Level))
+	}
+
+	d.mu.Lock()
+	d.logLevel = params.Level
+	d.mu.Unlock()
+	return &jsonRPCResponse{JSONRPC: "2.0", ID: request.ID, Result: struct{}{}}
+}
+
+// mcpLog 在请求处理过程中记录日志
+func mcpLog(ctx context.Context, level, logger, message string, fields logFields) {
+	call, _ := ctx.Value(mcpCallKey{}).(*mcpCall)
+	if call == nil || call.dispatcher == nil {
+		serveLog.log(level, logger, message, fields)
+		return
+	}
+	call.dispatcher.logTo(call.send, level, logger, message, fields)
+}
+
+// mcpTracer 将 GitHub API 调用记录为 debug 日志，启用指标时同时计入指标
+func mcpTracer(ctx context.Context) func(github.RequestTrace) {
+	return func(t github.RequestTrace) {
+		activeMetrics.observeGitHub(t)
+		fields := logFields{
+			"method":      t.Method,
+			"url":         t.URL,
+			"status":      t.Status,
+			"duration_ms": t.Duration.Milliseconds(),
+			"headers":     t.RequestHeaders,
+		}
+		if t.RateLimitRemaining != "" {
+			fields["ratelimit_remaining"] = t.RateLimitRemaining
+		}
+		level := "debug"
+		if t.Err != nil {
+			fields["error"] = t.Err.Error()
+			// 404 是探测 manifest、类型注册表时的正常结果
+			if !github.IsNotFound(t.Err) {
+				level = "warning"
+			}
+		}
+		mcpLog(ctx, level, "github", "GitHub API 调用", fields)
+	}
+}
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shichao402/github-issue-pack/internal/models"
+	"github.com/shichao402/github-issue-pack/internal/service"
+)
+
+// MCP 提示词相关消息
+type promptsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+type prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []promptArgument `json:"arguments,omitempty"`
+}
+
+type promptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type promptsListResult struct {
+	Prompts []prompt `json:"prompts"`
+}
+
+type getPromptParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments"`
+}
+
+type promptMessage struct {
+	Role    string      `json:"role"`
+	Content contentItem `json:"content"`
+}
+
+type getPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []promptMessage `json:"messages"`
+}
+
+// promptDefinition 提示词模板：参数定义及根据 Issue 生成指令的函数
+type promptDefinition struct {
+	prompt
+	build func(result *service.GetResult, args map[string]string) string
+}
+
+// issuePromptArguments 所有提示词共用的参数
+func issuePromptArguments(extra ...promptArgument) []promptArgument {
+	args := []promptArgument{
+		{Name: "repo", Description: "目标仓库 (格式: owner/repo)，profile 配置了默认仓库时可省略", Required: activeProfile.Repo == ""},
+		{Name: "number", Description: "Issue 编号", Required: true},
+	}
+	return append(args, extra...)
+}
+
+func promptDefinitions() []promptDefinition {
+	return []promptDefinition{
+		{
+			prompt: prompt{
+				Name:        "triage_issue",
+				Description: "分诊待处理的 Issue：评估完整性、优先级，并给出下一步处理建议",
+				Arguments:   issuePromptArguments(),
+			},
+			build: buildTriagePrompt,
+		},
+		{
+			prompt: prompt{
+				Name:        "draft_rejection",
+				Description: "起草拒绝该 Issue 的回复，说明原因和可行的替代方案",
+				Arguments: issuePromptArguments(promptArgument{
+					Name:        "reasons",
+					Description: "拒绝原因要点（可选）",
+				}),
+			},
+			build: buildRejectionPrompt,
+		},
+		{
+			prompt: prompt{
+				Name:        "reproduction_plan",
+				Description: "将 bug-report 转换为可执行的复现计划",
+				Arguments:   issuePromptArguments(),
+			},
+			build: buildReproductionPrompt,
+		},
+	}
+}
+
+func handleMCPPromptsList(request *jsonRPCRequest) *jsonRPCResponse {
+	var prompts []prompt
+	for _, def := range promptDefinitions() {
+		prompts = append(prompts, def.prompt)
+	}
+
+	return &jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      request.ID,
+		Result:  promptsListResult{Prompts: prompts},
+	}
+}
+
+func handleMCPPromptsGet(ctx context.Context, request *jsonRPCRequest) *jsonRPCResponse {
+	var params getPromptParams
+	if err := json.Unmarshal(request.Params, &params); err != nil {
+		return rpcErrorResponse(request.ID, -32602, "Invalid params", err.Error())
+	}
+
+	var def *promptDefinition
+	for _, d := range promptDefinitions() {
+		if d.Name == params.Name {
+			d := d
+			def = &d
+			break
+		}
+	}
+	if def == nil {
+		return rpcErrorResponse(request.ID, -32602, "Invalid params", fmt.Sprintf("未知的提示词: %s", params.Name))
+	}
+
+	repo := params.Arguments["repo"]
+	if repo == "" {
+		repo = activeProfile.Repo
+	}
+	number, err := strconv.Atoi(params.Arguments["number"])
+	if repo == "" || err != nil || number <= 0 {
+		return rpcErrorResponse(request.ID, -32602, "Invalid params", "缺少或无效的参数: repo, number")
+	}
+
+	token := getMCPToken()
+	if token == "" {
+		return rpcErrorResponse(request.ID, -32603, "无法获取 GitHub Token", nil)
+	}
+
+	svc := newMCPIssueService(ctx, token)
+	result, err := svc.Get(repo, number)
+	if err != nil {
+		return rpcErrorResponse(request.ID, -32603, "获取 Issue 失败", err.Error())
+	}
+
+	args := map[string]string{"repo": repo}
+	for k, v := range params.Arguments {
+		args[k] = v
+	}
+
+	return &jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      request.ID,
+		Result: getPromptResult{
+			Description: def.Description,
+			Messages: []promptMessage{{
+				Role:    "user",
+				Content: contentItem{Type: "text", Text: def.build(result, args)},
+			}},
+		},
+	}
+}
+
+// describeIssueForPrompt 将 Issue 和解包后的包整理为提示词上下文
+func describeIssueForPrompt(result *service.GetResult, repo string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "仓库: %s\n", repo)
+	fmt.Fprintf(&b, "Issue #%d: %s\n", result.Issue.Number, result.Issue.Title)
+	fmt.Fprintf(&b, "状态: %s\n", result.Issue.State)
+	fmt.Fprintf(&b, "发起人: %s\n", result.Issue.User.Login)
+	fmt.Fprintf(&b, "创建时间: %s\n", result.Issue.CreatedAt)
+	fmt.Fprintf(&b, "URL: %s\n", result.Issue.HTMLURL)
+
+	if result.Package == nil {
+		b.WriteString("\n该 Issue 没有关联的 Issue 包，以下为 Issue 正文:\n\n")
+		b.WriteString(result.Issue.Body)
+		return b.String()
+	}
+
+	data, _ := json.MarshalIndent(result.Package, "", "  ")
+	fmt.Fprintf(&b, "\nIssue 包 (类型 %s):\n```json\n%s\n```\n", result.Package.Type, string(data))
+	return b.String()
+}
+
+func buildTriagePrompt(result *service.GetResult, args map[string]string) string {
+	return fmt.Sprintf(`请分诊以下待处理的 Issue。
+
+%s
+请按以下步骤处理:
+1. 概括请求内容（一到两句话）。
+2. 检查 payload 是否完整：列出缺失或含糊的字段。
+3. 评估优先级（高/中/低）并说明理由。
+4. 给出建议的下一步：
+   - 信息完整且可以处理：使用 github_issue_update 将状态改为 processing，并附上处理计划评论；
+   - 需要更多信息：列出需要向发起人追问的问题，确认后使用 github_issue_update 将状态改为 needs-info 并在评论中提出问题；
+   - 与已有 Issue 重复：指出规范 Issue，确认后使用 github_issue_close (result=duplicate, duplicate_of=规范 Issue 编号) 关闭；
+   - 不应处理：说明原因，并建议使用 draft_rejection 起草拒绝回复。
+
+在我确认之前不要调用任何会修改 Issue 的工具。`, describeIssueForPrompt(result, args["repo"]))
+}
+
+func buildRejectionPrompt(result *service.GetResult, args map[string]string) string {
+	reasons := args["reasons"]
+	if reasons == "" {
+		reasons = "（未提供，请根据 Issue 内容和项目规范推断）"
+	}
+
+	return fmt.Sprintf(`请为以下 Issue 起草一份拒绝回复。
+
+%s
+拒绝原因要点: %s
+
+要求:
+- 语气礼貌、直接，先感谢发起人的提交；
+- 清晰说明拒绝原因，每条原因对应 Issue 包中的具体内容；
+- 如有可行的替代方案或重新提交的条件，一并给出；
+- 回复控制在 200 字以内，使用 Markdown。
+
+只输出回复正文。确认后可使用 github_issue_close (result=rejected, comment=回复正文) 关闭该 Issue。`,
+		describeIssueForPrompt(result, args["repo"]), reasons)
+}
+
+func buildReproductionPrompt(result *service.GetResult, args map[string]string) string {
+	note := ""
+	if result.Package != nil && result.Package.Type != models.TypeBugReport {
+		note = fmt.Sprintf("\n注意: 该 Issue 的类型是 %s 而不是 bug-report，请先判断是否适合制定复现计划。\n", result.Package.Type)
+	}
+
+	return fmt.Sprintf(`请将以下 bug-report 转换为可执行的复现计划。
+
+%s%s
+复现计划应包含:
+1. 环境准备：根据 environment 字段列出操作系统、相关版本和依赖；缺失的信息标注为待确认。
+2. 复现步骤：将 steps_to_reproduce 整理为编号的、可直接执行的步骤（包含具体命令）。
+3. 预期结果与实际结果：分别对应 expected_behavior 和 actual_behavior。
+4. 判定标准：如何确认问题已复现，以及修复后如何验证。
+5. 初步排查方向：根据描述和附件推测可能的原因及相关代码位置。`,
+		describeIssueForPrompt(result, args["repo"]), note)
+}
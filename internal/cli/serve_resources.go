@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+)
+
+// resourceScheme MCP 资源 URI 的 scheme
+const resourceScheme = "github-issue://"
+
+// MCP 资源相关消息
+type resourcesCapability struct {
+	Subscribe   bool `json:"subscribe,omitempty"`
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+type resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type resourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type resourcesListResult struct {
+	Resources []resource `json:"resources"`
+}
+
+type resourceTemplatesListResult struct {
+	ResourceTemplates []resourceTemplate `json:"resourceTemplates"`
+}
+
+type readResourceParams struct {
+	URI string `json:"uri"`
+}
+
+type resourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+type readResourceResult struct {
+	Contents []resourceContents `json:"contents"`
+}
+
+// resourceURIPattern github-issue://owner/repo/issues/123[/package|/attachments/name]
+var resourceURIPattern = regexp.MustCompile(`^github-issue://([^/]+)/([^/]+)/issues/(\d+)(?:/(package)|/attachments/(.+))?$`)
+
+// issueResourceURI 构建 Issue 资源 URI
+func issueResourceURI(repo string, number int) string {
+	return fmt.Sprintf("%s%s/issues/%d", resourceScheme, repo, number)
+}
+
+// attachmentResourceURI 构建附件资源 URI，附件名按路径段转义
+func attachmentResourceURI(repo string, number int, name string) string {
+	return fmt.Sprintf("%s/attachments/%s", issueResourceURI(repo, number), url.PathEscape(name))
+}
+
+func handleMCPResourcesList(ctx context.Context, request *jsonRPCRequest) *jsonRPCResponse {
+	resources := []resource{}
+
+	// 有默认仓库时列出其待处理的 Issue，其他仓库通过资源模板访问
+	repo := activeProfile.Repo
+	token := getMCPToken()
+	if repo != "" && token != "" {
+		svc := newMCPIssueService(ctx, token)
+		issues, err := svc.List(service.ListOptions{Repo: repo, Status: "pending", Limit: 50})
+		if err != nil {
+			// 资源模板仍可用，列表失败只记录日志，便于排查 Token 或仓库配置问题
+			mcpLog(ctx, "error", "resources", "列出默认仓库的 Issue 失败", logFields{"repo": repo, "error": err.Error()})
+		} else {
+			for _, issue := range issues {
+				uri := issueResourceURI(repo, issue.Number)
+				resources = append(resources,
+					resource{
+						URI:         uri,
+						Name:        fmt.Sprintf("#%d %s", issue.Number, issue.Title),
+						Description: fmt.Sprintf("%s Issue (%s)", issue.Type, issue.Status),
+						MimeType:    "application/json",
+					},
+					resource{
+						URI:         uri + "/package",
+						Name:        fmt.Sprintf("#%d package", issue.Number),
+						Description: "解包后的 IssuePackage",
+						MimeType:    "application/json",
+					},
+				)
+			}
+		}
+	}
+
+	return &jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      request.ID,
+		Result:  resourcesListResult{Resources: resources},
+	}
+}
+
+func handleMCPResourceTemplatesList(request *jsonRPCRequest) *jsonRPCResponse {
+	templates := []resourceTemplate{
+		{
+			URITemplate: resourceScheme + "{owner}/{repo}/issues/{number}",
+			Name:        "Issue",
+			Description: "Issue 信息及解包后的 IssuePackage",
+			MimeType:    "application/json",
+		},
+		{
+			URITemplate: resourceScheme + "{owner}/{repo}/issues/{number}/package",
+			Name:        "Issue 包",
+			Description: "解包后的 IssuePackage，附件作为独立资源一并返回",
+			MimeType:    "application/json",
+		},
+		{
+			URITemplate: resourceScheme + "{owner}/{repo}/issues/{number}/attachments/{name}",
+			Name:        "Issue 附件",
+			Description: "IssuePackage 中的单个附件",
+		},
+	}
+
+	return &jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      request.ID,
+		Result:  resourceTemplatesListResult{ResourceTemplates: templates},
+	}
+}
+
+func handleMCPResourcesRead(ctx context.Context, request *jsonRPCRequest) *jsonRPCResponse {
+	var params readResourceParams
+	if err := json.Unmarshal(request.Params, &params); err != nil {
+		return rpcErrorResponse(request.ID, -32602, "Invalid params", err.Error())
+	}
+
+	match := resourceURIPattern.FindStringSubmatch(params.URI)
+	if match == nil {
+		return rpcErrorResponse(request.ID, -32002, "Resource not found", params.URI)
+	}
+	repo := match[1] + "/" + match[2]
+	number, _ := strconv.Atoi(match[3])
+	isPackage := match[4] != ""
+	attachment, err := url.PathUnescape(match[5])
+	if err != nil {
+		return rpcErrorResponse(request.ID, -32602, "Invalid params", err.Error())
+	}
+
+	token := getMCPToken()
+	if token == "" {
+		return rpcErrorResponse(request.ID, -32603, "无法获取 GitHub Token", nil)
+	}
+
+	svc := newMCPIssueService(ctx, token)
+	result, err := svc.Get(repo, number)
+	if err != nil {
+		return rpcErrorResponse(request.ID, -32002, "Resource not found", err.Error())
+	}
+
+	var contents []resourceContents
+	switch {
+	case attachment != "":
+		if result.Package == nil {
+			return rpcErrorResponse(request.ID, -32002, "Resource not found", params.URI)
+		}
+		for _, att := range result.Package.Attachments {
+			if att.Name == attachment {
+				contents = append(contents, resourceContents{
+					URI:      params.URI,
+					MimeType: attachmentMimeType(att.Name),
+					Text:     att.Content,
+				})
+			}
+		}
+		if len(contents) == 0 {
+			return rpcErrorResponse(request.ID, -32002, "Resource not found", params.URI)
+		}
+
+	case isPackage:
+		if result.Package == nil {
+			return rpcErrorResponse(request.ID, -32002, "Resource not found", "该 Issue 没有关联的 Issue 包")
+		}
+		data, _ := json.MarshalIndent(result.Package, "", "  ")
+		contents = append(contents, resourceContents{
+			URI:      params.URI,
+			MimeType: "application/json",
+			Text:     string(data),
+		})
+		for _, att := range result.Package.Attachments {
+			contents = append(contents, resourceContents{
+				URI:      attachmentResourceURI(repo, number, att.Name),
+				MimeType: attachmentMimeType(att.Name),
+				Text:     att.Content,
+			})
+		}
+
+	default:
+		data, _ := json.MarshalIndent(buildGetOutput(result), "", "  ")
+		contents = append(contents, resourceContents{
+			URI:      params.URI,
+			MimeType: "application/json",
+			Text:     string(data),
+		})
+	}
+
+	return &jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      request.ID,
+		Result:  readResourceResult{Contents: contents},
+	}
+}
+
+// attachmentMimeType 根据附件扩展名推断 MIME 类型，默认 text/plain
+func attachmentMimeType(name string) string {
+	if t := mime.TypeByExtension(path.Ext(name)); t != "" {
+		return t
+	}
+	return "text/plain"
+}
+
+// rpcErrorResponse 构建 JSON-RPC 错误响应
+func rpcErrorResponse(id interface{}, code int, message string, data interface{}) *jsonRPCResponse {
+	return &jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &rpcError{
+			Code:    code,
+			Message: message,
+			Data:    data,
+		},
+	}
+}
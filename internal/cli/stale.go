@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var staleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "处理等待补充信息 (needs-info) 的 Issue",
+	Long: `检查处于 needs-info 状态的 Issue。
+
+  - 发起人在问题之后发表了评论或更新了 Issue 包: 恢复为 pending
+  - 超过 --remind-after 未回复: 发表评论提醒发起人（之后每隔同样时长提醒一次）
+  - 超过 --close-after 未回复: 以 rejected 关闭
+
+阈值设为 0 关闭对应动作。适合在 cron 或 CI 定时任务中运行。
+
+示例:
+  github-issue stale --repo owner/repo --dry-run
+  github-issue stale --repo owner/repo --remind-after 3d --close-after 2w
+  github-issue stale --repo owner/repo --remind-after 0 --close-after 0   # 只恢复已回复的 Issue`,
+	RunE: runStale,
+}
+
+var (
+	staleRepo        string
+	staleRemindAfter string
+	staleCloseAfter  string
+	staleDryRun      bool
+	staleFormat      string
+)
+
+func init() {
+	rootCmd.AddCommand(staleCmd)
+
+	staleCmd.Flags().StringVar(&staleRepo, "repo", "", "目标仓库 (owner/repo)")
+	staleCmd.Flags().StringVar(&staleRemindAfter, "remind-after", "7d", "提问后超过该时长未回复则提醒 (如 7d, 72h)")
+	staleCmd.Flags().StringVar(&staleCloseAfter, "close-after", "30d", "提问后超过该时长未回复则关闭")
+	staleCmd.Flags().BoolVar(&staleDryRun, "dry-run", false, "预览模式，只输出报告不修改 Issue")
+	formatFlag(staleCmd.Flags(), &staleFormat, "输出格式", "table", "json")
+
+	staleCmd.MarkFlagRequired("repo")
+}
+
+func runStale(cmd *cobra.Command, args []string) error {
+	remindAfter, err := service.ParseDuration(staleRemindAfter)
+	if err != nil {
+		return err
+	}
+	closeAfter, err := service.ParseDuration(staleCloseAfter)
+	if err != nil {
+		return err
+	}
+
+	svc := newIssueService(cmd)
+	report, err := svc.Stale(service.StaleOptions{
+		Repo:        staleRepo,
+		RemindAfter: remindAfter,
+		CloseAfter:  closeAfter,
+		DryRun:      staleDryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if staleFormat == "json" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if report.DryRun {
+		fmt.Println("=== Dry Run 模式 ===")
+	}
+
+	if len(report.Items) == 0 {
+		fmt.Println("没有等待补充信息的 Issue")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Issue\tAuthor\tAsked\tAction\tReason")
+	fmt.Fprintln(w, "-----\t------\t-----\t------\t------")
+	counts := map[service.StaleAction]int{}
+	for _, item := range report.Items {
+		reason := item.Reason
+		if item.Error != "" {
+			reason += ": " + item.Error
+		}
+		asked := item.AskedAt
+		if len(asked) >= 10 {
+			asked = asked[:10]
+		}
+		fmt.Fprintf(w, "#%d\t%s\t%s\t%s\t%s\n", item.Number, item.Author, asked, item.Action, reason)
+		if item.Error == "" {
+			counts[item.Action]++
+		}
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Printf("共 %d 个 Issue：恢复 %d 个，提醒 %d 个，关闭 %d 个\n", len(report.Items),
+		counts[service.StaleResumed], counts[service.StaleReminded], counts[service.StaleClosed])
+	return nil
+}
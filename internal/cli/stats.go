@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "统计 Issue 队列的处理情况和 SLA",
+	Long: `根据 Issue 的标签事件和评论统计队列的处理情况:
+
+  - 各类型、各状态的 Issue 数量
+  - 首次响应时长（非发起人的评论、离开 pending 或关闭中最早的一个）
+  - processing 阶段的时长，以及当前仍在处理中的数量
+  - 拒绝率 rejected / (processed + rejected)
+  - 当前全部 pending 的 Issue 中等待最久的一个
+
+数量和时长只统计 --since 内创建的 Issue；pending 统计不受 --since 限制。
+
+SLA 检查: pending 超过 --max-pending-age 的 Issue 视为超时，未指定时使用仓库
+manifest 中的 sla.first_response。加 --check-sla 时有超时的 Issue 则以非 0 退出，
+适合在 CI 定时任务中告警。
+
+示例:
+  github-issue stats --repo owner/repo
+  github-issue stats --repo owner/repo --since 7d --type pack-sync --format json
+  github-issue stats --repo owner/repo --max-pending-age 48h --check-sla`,
+	RunE: runStats,
+}
+
+var (
+	statsRepo          string
+	statsSince         string
+	statsType          string
+	statsMaxPendingAge string
+	statsCheckSLA      bool
+	statsFormat        string
+)
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVar(&statsRepo, "repo", "", "目标仓库 (owner/repo)")
+	statsCmd.Flags().StringVar(&statsSince, "since", "30d", "统计该时长内创建的 Issue (如 30d, 2w)，0 表示全部")
+	statsCmd.Flags().StringVar(&statsType, "type", "", "只统计该类型的 Issue")
+	statsCmd.Flags().StringVar(&statsMaxPendingAge, "max-pending-age", "", "pending 超过该时长视为超出 SLA，默认使用 manifest 的 sla.first_response")
+	statsCmd.Flags().BoolVar(&statsCheckSLA, "check-sla", false, "有超出 SLA 的 pending Issue 时以非 0 退出")
+	formatFlag(statsCmd.Flags(), &statsFormat, "输出格式", "table", "json")
+
+	statsCmd.MarkFlagRequired("repo")
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	since, err := service.ParseDuration(statsSince)
+	if err != nil {
+		return err
+	}
+	var maxPendingAge time.Duration
+	if statsMaxPendingAge != "" {
+		if maxPendingAge, err = service.ParseDuration(statsMaxPendingAge); err != nil {
+			return err
+		}
+	}
+
+	svc := newIssueService(cmd)
+	report, err := svc.Stats(service.StatsOptions{
+		Repo:          statsRepo,
+		Since:         since,
+		Type:          statsType,
+		MaxPendingAge: maxPendingAge,
+	})
+	if err != nil {
+		return err
+	}
+	if statsCheckSLA && report.SLA == nil {
+		return fmt.Errorf("--check-sla 需要 --max-pending-age 或仓库 manifest 中的 sla.first_response")
+	}
+
+	if statsFormat == "json" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		printStats(report)
+	}
+
+	if statsCheckSLA && len(report.SLA.Breaches) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d 个 pending Issue 超出 SLA (%s)", len(report.SLA.Breaches), report.SLA.MaxPendingAge)
+	}
+	return nil
+}
+
+func printStats(report *service.StatsReport) {
+	if report.Since != "" {
+		fmt.Printf("📊 %s（%s 以来创建的 %d 个 Issue）\n\n", report.Repo, report.Since[:10], report.Total)
+	} else {
+		fmt.Printf("📊 %s（全部 %d 个 Issue）\n\n", report.Repo, report.Total)
+	}
+
+	if report.Total > 0 {
+		types := make([]string, 0, len(report.Counts))
+		for t := range report.Counts {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprint(w, "Type")
+		for _, status := range service.Statuses {
+			fmt.Fprintf(w, "\t%s", status)
+		}
+		fmt.Fprintln(w, "\ttotal")
+		for _, t := range types {
+			fmt.Fprint(w, t)
+			total := 0
+			for _, status := range service.Statuses {
+				fmt.Fprintf(w, "\t%d", report.Counts[t][status])
+				total += report.Counts[t][status]
+			}
+			fmt.Fprintf(w, "\t%d\n", total)
+		}
+		w.Flush()
+		fmt.Println()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Metric\tCount\tMean\tMedian\tP90\tMax")
+	fmt.Fprintln(w, "------\t-----\t----\t------\t---\t---")
+	for _, row := range []struct {
+		name string
+		d    service.DurationStats
+	}{
+		{"first response", report.FirstResponse},
+		{"processing", report.Processing},
+	} {
+		if row.d.Count == 0 {
+			fmt.Fprintf(w, "%s\t0\t-\t-\t-\t-\n", row.name)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", row.name, row.d.Count,
+			formatHours(row.d.Mean), formatHours(row.d.Median), formatHours(row.d.P90), formatHours(row.d.Max))
+	}
+	w.Flush()
+	fmt.Println()
+
+	fmt.Printf("未响应: %d，处理中: %d\n", report.Unanswered, report.InProcessing)
+	fmt.Printf("已处理: %d，已拒绝: %d，重复: %d，拒绝率: %.1f%%\n",
+		report.Processed, report.Rejected, report.Duplicate, report.RejectionRate*100)
+	fmt.Printf("当前 pending: %d\n", report.Pending)
+	if p := report.OldestPending; p != nil {
+		fmt.Printf("等待最久: #%d %s（%s，已等待 %s）\n", p.Number, shortTitle(p.Title), p.Type, formatHours(p.AgeHours))
+	}
+
+	if sla := report.SLA; sla != nil {
+		fmt.Println()
+		if len(sla.Breaches) == 0 {
+			fmt.Printf("✅ SLA: 没有 pending 超过 %s 的 Issue（来自 %s）\n", sla.MaxPendingAge, sla.Source)
+			return
+		}
+		fmt.Printf("⚠️  SLA: %d 个 Issue pending 超过 %s（来自 %s）\n", len(sla.Breaches), sla.MaxPendingAge, sla.Source)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, item := range sla.Breaches {
+			fmt.Fprintf(w, "  #%d\t%s\t%s\t%s\n", item.Number, item.Type, formatHours(item.AgeHours), shortTitle(item.Title))
+		}
+		w.Flush()
+	}
+}
+
+// formatHours 以小时或天显示时长
+func formatHours(h float64) string {
+	if h >= 48 {
+		return fmt.Sprintf("%.1fd", h/24)
+	}
+	return fmt.Sprintf("%.1fh", h)
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/shichao402/github-issue-pack/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var typesCmd = &cobra.Command{
+	Use:   "types",
+	Short: "列出目标仓库可用的 Issue 类型",
+	Long: `列出内置 Issue 类型和目标仓库注册的自定义类型。
+
+目标仓库可在 .github/issue-pack/types/<name>.json 中注册类型:
+
+  {
+    "name": "security-report",
+    "label": "security",
+    "description": "安全问题报告",
+    "schema": {
+      "type": "object",
+      "required": ["title", "severity"],
+      "properties": {
+        "title": {"type": "string"},
+        "severity": {"type": "string", "enum": ["low", "medium", "high"]}
+      }
+    },
+    "body_template": "**Severity:** {{.Payload.severity}}"
+  }
+
+示例:
+  github-issue types --repo owner/repo
+  github-issue types --repo owner/repo --format json`,
+	RunE: runTypes,
+}
+
+var (
+	typesRepo   string
+	typesFormat string
+)
+
+func init() {
+	rootCmd.AddCommand(typesCmd)
+
+	typesCmd.Flags().StringVar(&typesRepo, "repo", "", "目标仓库 (owner/repo)")
+	formatFlag(typesCmd.Flags(), &typesFormat, "输出格式", "table", "json")
+
+	typesCmd.MarkFlagRequired("repo")
+}
+
+// typeInfo 类型列表中的一项
+type typeInfo struct {
+	Name        string          `json:"name"`
+	Source      string          `json:"source"` // builtin / registry
+	Label       string          `json:"label"`
+	Description string          `json:"description,omitempty"`
+	Schema      json.RawMessage `json:"schema,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+func runTypes(cmd *cobra.Command, args []string) error {
+	svc := newIssueService(cmd)
+	registry, err := svc.FetchTypeRegistry(typesRepo)
+	if err != nil {
+		return err
+	}
+	labels := svc.Labels(typesRepo)
+
+	var types []typeInfo
+	for _, name := range registry.Names() {
+		def := registry.Types[name]
+		label := labels.Type(name)
+		if _, mapped := labels.Types[name]; !mapped && def.Label != "" {
+			label = def.Label
+		}
+		types = append(types, typeInfo{
+			Name:        name,
+			Source:      "registry",
+			Label:       label,
+			Description: def.Description,
+			Schema:      def.Schema,
+		})
+	}
+	for _, t := range models.BuiltinTypes {
+		if _, ok := registry.Types[string(t)]; ok {
+			continue
+		}
+		types = append(types, typeInfo{
+			Name:   string(t),
+			Source: "builtin",
+			Label:  labels.Type(string(t)),
+		})
+	}
+	for _, name := range registry.InvalidNames() {
+		types = append(types, typeInfo{Name: name, Source: "registry", Error: registry.Invalid[name].Error()})
+	}
+
+	if typesFormat == "json" {
+		data, _ := json.MarshalIndent(types, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Type\tSource\tLabel\tDescription")
+	fmt.Fprintln(w, "----\t------\t-----\t-----------")
+	for _, t := range types {
+		desc := t.Description
+		if t.Error != "" {
+			desc = "⚠️ 定义无效: " + t.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.Name, t.Source, t.Label, desc)
+	}
+	w.Flush()
+	return nil
+}
@@ -1,22 +1,29 @@
 package cli
 
 import (
-	"fmt"
-	"strconv"
-
-	"github.com/shichao402/github-issue-pack/internal/service"
 	"github.com/spf13/cobra"
 )
 
 var updateCmd = &cobra.Command{
-	Use:   "update <issue-number>",
+	Use:   "update <issue-number>... | --query <条件>",
 	Short: "更新 Issue 状态",
 	Long: `更新 Issue 的状态标签。
 
 示例:
   github-issue update 123 --repo owner/repo --status processing
-  github-issue update 123 --repo owner/repo --status pending --comment "需要更多信息"`,
-	Args: cobra.ExactArgs(1),
+  github-issue update 123 --repo owner/repo --status needs-info --comment "请提供完整的错误日志"
+
+needs-info 的评论会作为结构化问题发布。发起人回复或更新 Issue 包后，
+github-issue stale 会将 Issue 恢复为 pending。
+
+批量更新：传入多个编号、"-"（从标准输入读取编号）或 --query 条件，
+列出将处理的 Issue 并确认后以 --concurrency 并发执行，最后输出每个 Issue 的结果：
+  github-issue update 101 102 103 --repo owner/repo --status processing
+  github-issue list --repo owner/repo --format json | jq '.[].number' | github-issue update - --repo owner/repo --status processing --yes
+  github-issue update --repo owner/repo --query "status:pending type:pack-sync older:3d" --status processing --dry-run
+
+--query 支持 status:、type:、older:（超过该时长未更新），其余词作为 GitHub 搜索条件。
+GitHub 搜索最多返回 1000 个结果，匹配更多时报错而不处理，请缩小条件分批处理。`,
 	RunE: runUpdate,
 }
 
@@ -24,37 +31,31 @@ var (
 	updateRepo    string
 	updateStatus  string
 	updateComment string
+	updateBulk    bulkFlags
 )
 
 func init() {
 	rootCmd.AddCommand(updateCmd)
 
 	updateCmd.Flags().StringVar(&updateRepo, "repo", "", "目标仓库 (owner/repo)")
-	updateCmd.Flags().StringVar(&updateStatus, "status", "", "新状态 (processing/pending)")
+	updateCmd.Flags().StringVar(&updateStatus, "status", "", "新状态 (processing/pending/needs-info)")
 	updateCmd.Flags().StringVar(&updateComment, "comment", "", "添加评论")
+	updateBulk.register(updateCmd)
+	updateCmd.Args = bulkArgs(&updateBulk)
 
 	updateCmd.MarkFlagRequired("repo")
 	updateCmd.MarkFlagRequired("status")
 }
 
 func runUpdate(cmd *cobra.Command, args []string) error {
-	token := getToken(cmd)
-
-	number, err := strconv.Atoi(args[0])
-	if err != nil {
-		return fmt.Errorf("无效的 Issue 编号: %s", args[0])
+	in := opInput{
+		"repo":    updateRepo,
+		"status":  updateStatus,
+		"comment": updateComment,
 	}
-
-	// 验证状态
-	if updateStatus != "processing" && updateStatus != "pending" {
-		return fmt.Errorf("无效的状态: %s，只能是 processing 或 pending", updateStatus)
-	}
-
-	svc := service.NewIssueService(token)
-	if err := svc.UpdateStatus(updateRepo, number, updateStatus, updateComment); err != nil {
-		return err
+	if updateBulk.isBulk(args) {
+		return runBulkOperation(cmd, "update", in, args, &updateBulk, "更新为 "+updateStatus)
 	}
-
-	fmt.Printf("✅ Issue #%d 状态已更新为 %s\n", number, updateStatus)
-	return nil
+	in["number"] = args[0]
+	return runOperation(cmd, "update", in, "text")
 }
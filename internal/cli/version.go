@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 
+	"github.com/shichao402/github-issue-pack/internal/models"
 	"github.com/spf13/cobra"
 )
 
@@ -18,4 +19,5 @@ var versionCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
+	models.ToolVersion = Version
 }
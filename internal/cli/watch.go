@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "持续监控仓库中的新 Issue 和状态变更",
+	Long: `按固定间隔轮询仓库，输出新的标准化 Issue 和状态变更。
+
+轮询使用 since 参数只获取上次之后更新过的 Issue，并通过 ETag 条件请求
+避免重复下载（没有变化时不消耗 API 配额）。游标保存在状态文件中，重启后
+从上次的位置继续。首次运行只记录现有 Issue，不输出事件（--include-existing 除外）。
+
+--exec 对每个事件执行一次命令，事件 JSON 通过 stdin 传入，同时设置环境变量
+GITHUB_ISSUE_EVENT、GITHUB_ISSUE_REPO、GITHUB_ISSUE_NUMBER、GITHUB_ISSUE_TYPE、
+GITHUB_ISSUE_STATUS、GITHUB_ISSUE_URL。
+
+示例:
+  github-issue watch --repo owner/repo --interval 60s
+  github-issue watch --repo owner/repo --format json >> events.jsonl
+  github-issue watch --repo owner/repo --exec 'notify-send "新 Issue #$GITHUB_ISSUE_NUMBER"'
+  github-issue watch --repo owner/repo --once   # 适合在 cron 中运行
+  github-issue watch --repo owner/repo --metrics-listen 127.0.0.1:9464`,
+	RunE: runWatch,
+}
+
+var (
+	watchRepo            string
+	watchInterval        time.Duration
+	watchFormat          string
+	watchExec            string
+	watchStateFile       string
+	watchOnce            bool
+	watchIncludeExisting bool
+	watchMetricsListen   string
+)
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().StringVar(&watchRepo, "repo", "", "目标仓库 (owner/repo)")
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", 60*time.Second, "轮询间隔")
+	formatFlag(watchCmd.Flags(), &watchFormat, "输出格式，json 为每行一个事件", "text", "json")
+	watchCmd.Flags().StringVar(&watchExec, "exec", "", "每个事件执行的命令")
+	watchCmd.Flags().StringVar(&watchStateFile, "state-file", "", "游标状态文件 (默认 <用户缓存目录>/github-issue/watch/<owner>_<repo>.json)")
+	watchCmd.Flags().BoolVar(&watchOnce, "once", false, "只轮询一次后退出")
+	watchCmd.Flags().BoolVar(&watchIncludeExisting, "include-existing", false, "首次运行时将现有 Issue 作为新事件输出")
+	watchCmd.Flags().StringVar(&watchMetricsListen, "metrics-listen", "", "Prometheus 指标端点的监听地址 (如 127.0.0.1:9464)，默认不启用")
+
+	watchCmd.MarkFlagRequired("repo")
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if watchFormat != "text" && watchFormat != "json" {
+		return fmt.Errorf("无效的输出格式: %s (text/json)", watchFormat)
+	}
+	if watchInterval < time.Second {
+		return fmt.Errorf("轮询间隔不能小于 1s")
+	}
+
+	statePath := watchStateFile
+	if statePath == "" {
+		var err error
+		if statePath, err = defaultWatchStatePath(watchRepo); err != nil {
+			return err
+		}
+	}
+	state, err := service.LoadWatchState(statePath, watchRepo)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	svc := newIssueService(cmd).WithContext(ctx)
+	if watchMetricsListen != "" && !watchOnce {
+		if err := startMetricsServer(watchMetricsListen); err != nil {
+			return err
+		}
+		svc = svc.WithTracer(activeMetrics.observeGitHub)
+	}
+
+	for {
+		events, err := svc.Poll(state, watchIncludeExisting)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			if watchOnce {
+				return err
+			}
+			// 网络错误等在下次轮询时重试
+			fmt.Fprintf(os.Stderr, "警告: 轮询失败: %v\n", err)
+			activeMetrics.inboxFailed(watchRepo)
+		} else {
+			for _, event := range events {
+				printWatchEvent(event)
+				if watchExec != "" {
+					runWatchExec(ctx, event)
+				}
+			}
+			if err := state.Save(statePath); err != nil {
+				return err
+			}
+			// 收件箱深度取自游标状态，不需要额外的 API 调用
+			activeMetrics.setInbox(watchRepo, state.InboxDepth())
+		}
+
+		if watchOnce {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchInterval):
+		}
+	}
+}
+
+// defaultWatchStatePath 返回仓库的默认状态文件路径
+func defaultWatchStatePath(repo string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("无法确定缓存目录，请使用 --state-file: %w", err)
+	}
+	name := strings.ReplaceAll(repo, "/", "_") + ".json"
+	return filepath.Join(dir, "github-issue", "watch", name), nil
+}
+
+func printWatchEvent(event service.WatchEvent) {
+	if watchFormat == "json" {
+		data, _ := json.Marshal(event)
+		fmt.Println(string(data))
+		return
+	}
+
+	now := time.Now().Format("2006-01-02 15:04:05")
+	switch event.Event {
+	case service.WatchEventStatusChanged:
+		fmt.Printf("[%s] #%d 状态变更: %s → %s  %s\n", now, event.Number, event.PreviousStatus, event.Status, event.Title)
+	default:
+		fmt.Printf("[%s] 新 Issue #%d [%s] %s (%s)\n", now, event.Number, event.Type, event.Title, event.Status)
+	}
+}
+
+// runWatchExec 执行 --exec 命令，失败时只输出警告
+func runWatchExec(ctx context.Context, event service.WatchEvent) {
+	var c *exec.Cmd
+	if runtime.GOOS == "windows" {
+		c = exec.CommandContext(ctx, "cmd", "/C", watchExec)
+	} else {
+		c = exec.CommandContext(ctx, "sh", "-c", watchExec)
+	}
+
+	data, _ := json.Marshal(event)
+	c.Stdin = strings.NewReader(string(data) + "\n")
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(),
+		"GITHUB_ISSUE_EVENT="+event.Event,
+		"GITHUB_ISSUE_REPO="+event.Repo,
+		"GITHUB_ISSUE_NUMBER="+strconv.Itoa(event.Number),
+		"GITHUB_ISSUE_TYPE="+event.Type,
+		"GITHUB_ISSUE_STATUS="+event.Status,
+		"GITHUB_ISSUE_URL="+event.URL,
+	)
+	if err := c.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "警告: #%d 执行命令失败: %v\n", event.Number, err)
+	}
+}
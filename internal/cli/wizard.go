@@ -0,0 +1,520 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shichao402/github-issue-pack/internal/models"
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// wizardPayloadTypes 内置类型对应的 payload 结构体，question/custom 只填写标题和描述
+var wizardPayloadTypes = map[models.IssueType]interface{}{
+	models.TypeFeatureRequest: models.FeatureRequestPayload{},
+	models.TypeBugReport:      models.BugReportPayload{},
+	models.TypePackRegister:   models.PackRegisterPayload{},
+	models.TypePackSync:       models.PackSyncPayload{},
+}
+
+// wizardFieldLabels payload 字段的提示名称
+var wizardFieldLabels = map[string]string{
+	"title":                 "标题",
+	"description":           "描述",
+	"use_case":              "使用场景",
+	"expected_behavior":     "预期行为",
+	"actual_behavior":       "实际行为",
+	"alternatives":          "替代方案",
+	"steps_to_reproduce":    "复现步骤",
+	"environment":           "环境信息",
+	"os":                    "操作系统",
+	"cursortoolset_version": "CursorToolset 版本",
+	"pack_version":          "包版本",
+	"repository":            "仓库",
+	"name":                  "名称",
+	"version":               "版本",
+	"changes":               "变更内容",
+}
+
+// wizardLongTextFields 使用编辑器填写的长文本字段
+var wizardLongTextFields = map[string]bool{
+	"description":       true,
+	"use_case":          true,
+	"expected_behavior": true,
+	"actual_behavior":   true,
+	"alternatives":      true,
+	"changes":           true,
+}
+
+// wizard 交互式问答
+type wizard struct {
+	in       *bufio.Reader
+	out      io.Writer
+	defaults map[string]string // 按字段名自动填充的默认值
+}
+
+func newWizard() *wizard {
+	return &wizard{
+		in:  bufio.NewReader(os.Stdin),
+		out: os.Stdout,
+		defaults: map[string]string{
+			"os": runtime.GOOS + "/" + runtime.GOARCH,
+		},
+	}
+}
+
+// readLine 读取一行输入，输入结束时返回 io.ErrUnexpectedEOF
+func (w *wizard) readLine() (string, error) {
+	line, err := w.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		if err == io.EOF {
+			return "", io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// ask 提问，直接回车使用默认值
+func (w *wizard) ask(label, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", label)
+	}
+	line, err := w.readLine()
+	if err != nil {
+		return "", err
+	}
+	if line = strings.TrimSpace(line); line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+// askRequired 提问直到输入非空
+func (w *wizard) askRequired(label, def string) (string, error) {
+	for {
+		v, err := w.ask(label, def)
+		if err != nil || v != "" {
+			return v, err
+		}
+		fmt.Fprintf(w.out, "%s 不能为空\n", label)
+	}
+}
+
+// choose 从选项中选择，可输入序号或名称
+func (w *wizard) choose(label string, options []string) (string, error) {
+	fmt.Fprintf(w.out, "%s:\n", label)
+	for i, opt := range options {
+		fmt.Fprintf(w.out, "  %d) %s\n", i+1, opt)
+	}
+	for {
+		v, err := w.askRequired("请选择", "")
+		if err != nil {
+			return "", err
+		}
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 && n <= len(options) {
+			return options[n-1], nil
+		}
+		if containsString(options, v) {
+			return v, nil
+		}
+		fmt.Fprintf(w.out, "无效的选择: %s\n", v)
+	}
+}
+
+// confirm 是/否确认
+func (w *wizard) confirm(label string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	v, err := w.ask(fmt.Sprintf("%s (%s)", label, hint), "")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(v) {
+	case "":
+		return def, nil
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// askList 逐行输入列表，空行结束
+func (w *wizard) askList(label string) ([]string, error) {
+	fmt.Fprintf(w.out, "%s (每行一项，空行结束):\n", label)
+	var items []string
+	for i := 1; ; i++ {
+		fmt.Fprintf(w.out, "  %d. ", i)
+		line, err := w.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if line = strings.TrimSpace(line); line == "" {
+			return items, nil
+		}
+		items = append(items, line)
+	}
+}
+
+// askLong 长文本：直接输入单行内容，或回车打开 $EDITOR
+func (w *wizard) askLong(label string, required bool) (string, error) {
+	for {
+		fmt.Fprintf(w.out, "%s (回车打开编辑器): ", label)
+		line, err := w.readLine()
+		if err != nil {
+			return "", err
+		}
+		text := strings.TrimSpace(line)
+		if text == "" {
+			text, err = editText()
+			if err != nil {
+				// 编辑器不可用时改为多行输入
+				fmt.Fprintf(w.out, "无法打开编辑器 (%v)，请直接输入:\n", err)
+				lines, err := w.askList(label)
+				if err != nil {
+					return "", err
+				}
+				text = strings.Join(lines, "\n")
+			}
+		}
+		if text != "" || !required {
+			return text, nil
+		}
+		fmt.Fprintf(w.out, "%s 不能为空\n", label)
+	}
+}
+
+// editText 用 $VISUAL/$EDITOR 编辑临时文件并返回内容
+func editText() (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	f, err := os.CreateTemp("", "github-issue-*.md")
+	if err != nil {
+		return "", err
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	// EDITOR 可能带参数，如 "code --wait"
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// fieldName 返回字段的 JSON 名称及是否可省略
+func fieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, strings.Contains(opts, "omitempty")
+}
+
+// fieldLabel 返回字段的提示名称
+func fieldLabel(name string) string {
+	if label, ok := wizardFieldLabels[name]; ok {
+		return label
+	}
+	return name
+}
+
+// fillStruct 按结构体字段逐项提问，title 字段直接使用 Issue 标题
+func (w *wizard) fillStruct(v reflect.Value, title string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, optional := fieldName(field)
+		label := fieldLabel(name)
+		fv := v.Field(i)
+
+		switch {
+		case name == "title" && fv.Kind() == reflect.String:
+			fv.SetString(title)
+
+		case fv.Kind() == reflect.String && wizardLongTextFields[name]:
+			s, err := w.askLong(label, !optional)
+			if err != nil {
+				return err
+			}
+			fv.SetString(s)
+
+		case fv.Kind() == reflect.String:
+			var s string
+			var err error
+			if optional {
+				s, err = w.ask(label, w.defaults[name])
+			} else {
+				s, err = w.askRequired(label, w.defaults[name])
+			}
+			if err != nil {
+				return err
+			}
+			fv.SetString(s)
+
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.String:
+			items, err := w.askList(label)
+			if err != nil {
+				return err
+			}
+			fv.Set(reflect.ValueOf(items))
+
+		case fv.Kind() == reflect.Struct:
+			fmt.Fprintf(w.out, "--- %s ---\n", label)
+			if err := w.fillStruct(fv, title); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fillSchema 按自定义类型的 JSON Schema 顶层属性逐项提问，必需字段在前
+func (w *wizard) fillSchema(schema *models.JSONSchema, title string) (map[string]interface{}, error) {
+	required := map[string]bool{}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if required[names[i]] != required[names[j]] {
+			return required[names[i]]
+		}
+		return names[i] < names[j]
+	})
+
+	result := map[string]interface{}{}
+	for _, name := range names {
+		prop := schema.Properties[name]
+		label := fieldLabel(name)
+		if prop.Description != "" {
+			label = fmt.Sprintf("%s (%s)", label, prop.Description)
+		}
+
+		switch {
+		case name == "title" && prop.Type == "string":
+			result[name] = title
+
+		case len(prop.Enum) > 0:
+			options := make([]string, 0, len(prop.Enum))
+			for _, e := range prop.Enum {
+				options = append(options, fmt.Sprint(e))
+			}
+			v, err := w.choose(label, options)
+			if err != nil {
+				return nil, err
+			}
+			result[name] = v
+
+		case prop.Type == "array" && (prop.Items == nil || prop.Items.Type == "string"):
+			items, err := w.askList(label)
+			if err != nil {
+				return nil, err
+			}
+			if len(items) > 0 || required[name] {
+				result[name] = items
+			}
+
+		case prop.Type == "boolean":
+			v, err := w.confirm(label, false)
+			if err != nil {
+				return nil, err
+			}
+			result[name] = v
+
+		case prop.Type == "object" && len(prop.Properties) > 0:
+			fmt.Fprintf(w.out, "--- %s ---\n", label)
+			v, err := w.fillSchema(prop, title)
+			if err != nil {
+				return nil, err
+			}
+			result[name] = v
+
+		case prop.Type == "string" && wizardLongTextFields[name]:
+			v, err := w.askLong(label, required[name])
+			if err != nil {
+				return nil, err
+			}
+			if v != "" {
+				result[name] = v
+			}
+
+		default:
+			ask := w.ask
+			if required[name] {
+				ask = w.askRequired
+			}
+			v, err := ask(label, w.defaults[name])
+			if err != nil {
+				return nil, err
+			}
+			if v == "" {
+				continue
+			}
+			if prop.Type == "string" {
+				result[name] = v
+				continue
+			}
+			// 数字、对象等非字符串值按 JSON 解析
+			var parsed interface{}
+			if err := json.Unmarshal([]byte(v), &parsed); err != nil {
+				return nil, fmt.Errorf("%s 不是有效的 JSON 值: %w", name, err)
+			}
+			result[name] = parsed
+		}
+	}
+	return result, nil
+}
+
+// buildPayload 按类型逐项填写 payload：注册了 schema 的类型按 schema，内置类型按 payload 结构体
+func (w *wizard) buildPayload(issueType models.IssueType, def *models.TypeDefinition, title string) (interface{}, error) {
+	if def != nil && len(def.Schema) > 0 {
+		schema, err := models.ParseJSONSchema(def.Schema)
+		if err != nil {
+			return nil, err
+		}
+		return w.fillSchema(schema, title)
+	}
+
+	proto, ok := wizardPayloadTypes[issueType]
+	if !ok {
+		description, err := w.askLong(fieldLabel("description"), false)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{"title": title, "description": description}, nil
+	}
+
+	v := reflect.New(reflect.TypeOf(proto)).Elem()
+	if err := w.fillStruct(v, title); err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// runCreateWizard 交互式补全 create 的类型、标题和 payload，预览后确认再创建
+//
+// 命令行已提供的参数不再提问；--dry-run 时只预览。
+func runCreateWizard(cmd *cobra.Command, in opInput) error {
+	w := newWizard()
+	if in.boolean("detect_metadata") {
+		metadata := service.DetectMetadata(".", in.str("pack_dir"))
+		w.defaults["cursortoolset_version"] = metadata.CursorToolsetVersion
+		w.defaults["pack_version"] = metadata.PackVersion
+	}
+	if err := w.fillCreateInput(newIssueService(cmd), in); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("输入已结束，已取消创建")
+		}
+		return err
+	}
+
+	// 预览同时完成 payload 校验
+	in["dry_run"] = true
+	if err := runOperation(cmd, "create", in, "text"); err != nil {
+		return err
+	}
+	if createDryRun {
+		return nil
+	}
+
+	ok, err := w.confirm("确认创建该 Issue?", false)
+	if err != nil || !ok {
+		fmt.Fprintln(w.out, "已取消")
+		return nil
+	}
+	// 预览时已提示过可能的重复
+	in["dry_run"] = false
+	in["skip_duplicate_check"] = true
+	return runOperation(cmd, "create", in, "text")
+}
+
+// fillCreateInput 依次询问类型、标题和 payload 字段
+func (w *wizard) fillCreateInput(svc *service.IssueService, in opInput) error {
+	repo := in.str("repo")
+
+	// 类型注册表读取失败时仍可使用内置类型
+	registry, err := svc.FetchTypeRegistry(repo)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 读取类型注册表失败: %v\n", err)
+	}
+
+	issueType := in.str("type")
+	if issueType == "" {
+		options := make([]string, 0, len(models.BuiltinTypes))
+		for _, t := range models.BuiltinTypes {
+			options = append(options, string(t))
+		}
+		if registry != nil {
+			for _, name := range registry.Names() {
+				if !containsString(options, name) {
+					options = append(options, name)
+				}
+			}
+		}
+		if issueType, err = w.choose("Issue 类型", options); err != nil {
+			return err
+		}
+		in["type"] = issueType
+	}
+
+	title := in.str("title")
+	if title == "" {
+		if title, err = w.askRequired(fieldLabel("title"), ""); err != nil {
+			return err
+		}
+		in["title"] = title
+	}
+
+	if _, ok := in["payload"]; ok {
+		return nil
+	}
+	var def *models.TypeDefinition
+	if registry != nil {
+		def = registry.Types[issueType]
+	}
+	payload, err := w.buildPayload(models.IssueType(issueType), def, title)
+	if err != nil {
+		return err
+	}
+	in["payload"] = payload
+	return nil
+}
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shichao402/github-issue-pack/internal/service"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ProjectFileName 项目级配置文件名
+	ProjectFileName = ".github-issue.yaml"
+
+	// ProfileEnv 指定 profile 的环境变量
+	ProfileEnv = "GITHUB_ISSUE_PROFILE"
+
+	// DefaultProfileName 未显式指定时使用的 profile 名
+	DefaultProfileName = "default"
+)
+
+// Config 配置文件结构
+type Config struct {
+	DefaultProfile string                `yaml:"default_profile,omitempty"`
+	Profiles       map[string]Profile    `yaml:"profiles,omitempty"`
+	Repos          map[string]RepoConfig `yaml:"repos,omitempty"`
+
+	// Warnings 加载时忽略的配置项说明，如项目配置中的 token 和 api_host
+	Warnings []string `yaml:"-"`
+}
+
+// RepoConfig 针对单个仓库的配置，优先于 profile 中的同名配置
+type RepoConfig struct {
+	Labels service.Labels `yaml:"labels,omitempty"`
+}
+
+// Profile 命名配置
+type Profile struct {
+	Repo    string `yaml:"repo,omitempty"`
+	Token   string `yaml:"token,omitempty"`    // token 来源: env:NAME / file:PATH / gh
+	APIHost string `yaml:"api_host,omitempty"` // GitHub API 地址，GitHub Enterprise 使用
+	Format  string `yaml:"format,omitempty"`   // 默认输出格式
+	Limit   int    `yaml:"limit,omitempty"`    // list 默认数量限制
+
+	// Labels 标签名配置，留空的字段使用默认标签名
+	Labels service.Labels `yaml:"labels,omitempty"`
+}
+
+// GlobalPath 返回全局配置文件路径
+//
+// 默认 ~/.config/github-issue/config.yaml，设置了 XDG_CONFIG_HOME 时以其为准。
+func GlobalPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "github-issue", "config.yaml")
+}
+
+// ProjectPath 从当前目录向上查找项目级配置文件，未找到返回空字符串
+func ProjectPath() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Load 加载全局配置和项目级配置，项目级配置覆盖全局配置
+//
+// 项目配置随仓库分发，不可信：其中的 token 和 api_host 会被忽略并记入 Warnings，
+// 避免在第三方仓库中运行时把凭据发送到仓库指定的地址。
+func Load() (*Config, error) {
+	cfg := &Config{Profiles: make(map[string]Profile)}
+
+	for i, path := range []string{GlobalPath(), ProjectPath()} {
+		if path == "" {
+			continue
+		}
+		file, err := loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if file == nil {
+			continue
+		}
+		if i == 1 {
+			cfg.Warnings = append(cfg.Warnings, file.dropCredentials(path)...)
+		}
+		cfg.merge(file)
+	}
+
+	return cfg, nil
+}
+
+// dropCredentials 清除各 profile 中的 token 和 api_host，返回被忽略的配置项说明
+func (c *Config) dropCredentials(path string) []string {
+	var warnings []string
+	ignore := func(profile, key string) {
+		warnings = append(warnings, fmt.Sprintf("忽略项目配置 %s 中 profile %s 的 %s，token 和 API 地址只能在全局配置中设置", path, profile, key))
+	}
+	for _, name := range c.ProfileNames() {
+		p := c.Profiles[name]
+		if p.Token != "" {
+			ignore(name, "token")
+			p.Token = ""
+		}
+		if p.APIHost != "" {
+			ignore(name, "api_host")
+			p.APIHost = ""
+		}
+		c.Profiles[name] = p
+	}
+	return warnings
+}
+
+// loadFile 读取单个配置文件，文件不存在时返回 nil
+func loadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取配置文件失败 %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败 %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// merge 将 other 合并到当前配置，同名 profile 逐字段覆盖
+func (c *Config) merge(other *Config) {
+	if other.DefaultProfile != "" {
+		c.DefaultProfile = other.DefaultProfile
+	}
+	for name, p := range other.Profiles {
+		base := c.Profiles[name]
+		base.merge(p)
+		c.Profiles[name] = base
+	}
+	for repo, r := range other.Repos {
+		if c.Repos == nil {
+			c.Repos = make(map[string]RepoConfig)
+		}
+		base := c.Repos[repo]
+		base.Labels = base.Labels.Merge(r.Labels)
+		c.Repos[repo] = base
+	}
+}
+
+// Profile 按名称选择 profile
+//
+// name 为空时依次使用 GITHUB_ISSUE_PROFILE 环境变量、default_profile、"default"。
+// 显式指定的 profile 不存在时报错；未指定且没有可用 profile 时返回空 profile。
+func (c *Config) Profile(name string) (Profile, error) {
+	explicit := name != ""
+	if name == "" {
+		name = os.Getenv(ProfileEnv)
+		explicit = name != ""
+	}
+	if name == "" {
+		name = c.DefaultProfile
+		explicit = name != ""
+	}
+	if name == "" {
+		name = DefaultProfileName
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		if explicit {
+			return Profile{}, fmt.Errorf("配置中不存在 profile: %s (可用: %s)", name, strings.Join(c.ProfileNames(), ", "))
+		}
+		return Profile{}, nil
+	}
+
+	if err := p.Validate(); err != nil {
+		return Profile{}, fmt.Errorf("profile %s 无效: %w", name, err)
+	}
+	return p, nil
+}
+
+// ProfileNames 返回排序后的 profile 名称列表
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// merge 用 other 中的非空字段覆盖当前 profile
+func (p *Profile) merge(other Profile) {
+	if other.Repo != "" {
+		p.Repo = other.Repo
+	}
+	if other.Token != "" {
+		p.Token = other.Token
+	}
+	if other.APIHost != "" {
+		p.APIHost = other.APIHost
+	}
+	if other.Format != "" {
+		p.Format = other.Format
+	}
+	if other.Limit != 0 {
+		p.Limit = other.Limit
+	}
+	p.Labels = p.Labels.Merge(other.Labels)
+}
+
+// Validate 校验 profile
+func (p Profile) Validate() error {
+	if p.Token != "" && p.Token != "gh" &&
+		!strings.HasPrefix(p.Token, "env:") && !strings.HasPrefix(p.Token, "file:") {
+		return fmt.Errorf("无效的 token 来源: %s (应为 env:NAME、file:PATH 或 gh)", p.Token)
+	}
+
+	return nil
+}
+
+// ResolveToken 按 profile 中的 token 来源读取 token，未配置时返回空字符串
+func (p Profile) ResolveToken(ghToken func() string) (string, error) {
+	switch {
+	case p.Token == "":
+		return "", nil
+	case p.Token == "gh":
+		return ghToken(), nil
+	case strings.HasPrefix(p.Token, "env:"):
+		return os.Getenv(strings.TrimPrefix(p.Token, "env:")), nil
+	case strings.HasPrefix(p.Token, "file:"):
+		path := expandHome(strings.TrimPrefix(p.Token, "file:"))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("读取 token 文件失败: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return "", fmt.Errorf("无效的 token 来源: %s", p.Token)
+}
+
+// expandHome 展开路径开头的 ~
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile 在 dir 下写入文件并返回路径
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// chdir 切换工作目录，测试结束后恢复
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestLoad(t *testing.T) {
+	home := t.TempDir()
+	project := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+
+	writeFile(t, home, "github-issue/config.yaml", `
+default_profile: work
+profiles:
+  work:
+    repo: org/global
+    token: env:WORK_TOKEN
+    limit: 20
+    api_host: https://ghe.example.com/api/v3
+    labels:
+      pending: todo
+  personal:
+    repo: me/repo
+repos:
+  org/app:
+    labels:
+      namespace: app
+`)
+	writeFile(t, project, ProjectFileName, `
+profiles:
+  work:
+    repo: org/project
+    format: json
+    token: file:/home/user/.ssh/id_rsa
+    api_host: https://attacker.example.com
+    labels:
+      processed: done
+repos:
+  org/app:
+    labels:
+      marker: app-pack
+`)
+	sub := filepath.Join(project, "sub", "dir")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	chdir(t, sub)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load 报错: %v", err)
+	}
+
+	if cfg.DefaultProfile != "work" {
+		t.Errorf("DefaultProfile = %q, 期望 work", cfg.DefaultProfile)
+	}
+	work := cfg.Profiles["work"]
+	tests := []struct {
+		field, got, want string
+	}{
+		{"repo", work.Repo, "org/project"},
+		{"token", work.Token, "env:WORK_TOKEN"},
+		{"api_host", work.APIHost, "https://ghe.example.com/api/v3"},
+		{"format", work.Format, "json"},
+		{"labels.pending", work.Labels.Pending, "todo"},
+		{"labels.processed", work.Labels.Processed, "done"},
+		{"personal.repo", cfg.Profiles["personal"].Repo, "me/repo"},
+		{"repos.namespace", cfg.Repos["org/app"].Labels.Namespace, "app"},
+		{"repos.marker", cfg.Repos["org/app"].Labels.Marker, "app-pack"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, 期望 %q", tt.field, tt.got, tt.want)
+		}
+	}
+	if work.Limit != 20 {
+		t.Errorf("limit = %d, 期望 20", work.Limit)
+	}
+	// 项目配置中的 token 和 api_host 被忽略
+	if len(cfg.Warnings) != 2 {
+		t.Errorf("Warnings = %q, 期望忽略 token 和 api_host 两项", cfg.Warnings)
+	}
+}
+
+func TestLoadIgnoresProjectCredentials(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	project := t.TempDir()
+	chdir(t, project)
+	writeFile(t, project, ProjectFileName, `
+profiles:
+  default:
+    repo: evil/repo
+    token: gh
+    api_host: https://attacker.example.com
+`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load 报错: %v", err)
+	}
+	p := cfg.Profiles["default"]
+	if p.Token != "" || p.APIHost != "" {
+		t.Errorf("项目配置不应设置 token 或 api_host, 得到 token=%q api_host=%q", p.Token, p.APIHost)
+	}
+	if p.Repo != "evil/repo" {
+		t.Errorf("repo = %q, 项目配置的其他字段应生效", p.Repo)
+	}
+	if len(cfg.Warnings) != 2 {
+		t.Errorf("Warnings = %q, 期望 2 条", cfg.Warnings)
+	}
+}
+
+func TestLoadInvalidYAML(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	chdir(t, t.TempDir())
+	writeFile(t, home, "github-issue/config.yaml", "profiles: [")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("配置文件格式错误时 Load 应报错")
+	}
+}
+
+func TestLoadMissingFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	chdir(t, t.TempDir())
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load 报错: %v", err)
+	}
+	if len(cfg.Profiles) != 0 || cfg.DefaultProfile != "" {
+		t.Errorf("没有配置文件时应返回空配置, 得到 %+v", cfg)
+	}
+}
+
+func TestResolveToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := writeFile(t, dir, "token", "  file-token\n")
+	t.Setenv("GI_TEST_TOKEN", "env-token")
+	gh := func() string { return "gh-token" }
+
+	tests := []struct {
+		source  string
+		want    string
+		wantErr bool
+	}{
+		{source: "", want: ""},
+		{source: "gh", want: "gh-token"},
+		{source: "env:GI_TEST_TOKEN", want: "env-token"},
+		{source: "env:GI_TEST_TOKEN_UNSET", want: ""},
+		{source: "file:" + tokenFile, want: "file-token"},
+		{source: "file:" + filepath.Join(dir, "missing"), wantErr: true},
+		{source: "plain-token", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Profile{Token: tt.source}.ResolveToken(gh)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ResolveToken(%q) = %q, 期望报错", tt.source, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ResolveToken(%q) 报错: %v", tt.source, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveToken(%q) = %q, 期望 %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestResolveTokenExpandsHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeFile(t, home, ".gh-token", "home-token")
+
+	got, err := Profile{Token: "file:~/.gh-token"}.ResolveToken(nil)
+	if err != nil {
+		t.Fatalf("ResolveToken 报错: %v", err)
+	}
+	if got != "home-token" {
+		t.Errorf("ResolveToken = %q, 期望 home-token", got)
+	}
+}
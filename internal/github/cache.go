@@ -0,0 +1,168 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Cache 磁盘上的 API 响应缓存
+//
+// http/ 下保存带 ETag 或 Last-Modified 的 GET 响应，再次请求时发送条件请求，
+// 304 响应直接使用缓存内容（不消耗 API 配额）；缓存按 token 隔离。
+// gists/ 下保存按 SHA 索引的 Gist 版本，版本内容不可变，命中后不再请求。
+type Cache struct {
+	dir string
+}
+
+// NewCache 创建使用指定目录的缓存
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// DefaultCacheDir 返回默认缓存目录 <用户缓存目录>/github-issue/cache
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("无法确定缓存目录: %w", err)
+	}
+	return filepath.Join(dir, "github-issue", "cache"), nil
+}
+
+// Dir 返回缓存目录
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// cacheEntry 缓存的响应
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Body         []byte    `json:"body"`
+	StoredAt     time.Time `json:"stored_at"`
+}
+
+// entryPath 按 token 和 URL 计算缓存文件路径
+func (c *Cache) entryPath(token, url string) string {
+	sum := sha256.Sum256([]byte(token + "\n" + url))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, "http", key[:2], key+".json")
+}
+
+func (c *Cache) get(token, url string) *cacheEntry {
+	data, err := os.ReadFile(c.entryPath(token, url))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if json.Unmarshal(data, &entry) != nil || entry.URL != url {
+		return nil
+	}
+	return &entry
+}
+
+// put 保存响应，写入失败时忽略（缓存只用于加速）
+func (c *Cache) put(token string, entry *cacheEntry) {
+	entry.StoredAt = time.Now().UTC()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	writeFileAtomic(c.entryPath(token, entry.URL), data)
+}
+
+var shaPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+func (c *Cache) revisionPath(sha string) string {
+	return filepath.Join(c.dir, "gists", sha[:2], sha+".json")
+}
+
+// getRevision 读取 Gist 版本，未缓存时返回 nil
+func (c *Cache) getRevision(sha string) []byte {
+	if !shaPattern.MatchString(sha) {
+		return nil
+	}
+	data, err := os.ReadFile(c.revisionPath(sha))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// putRevision 保存 Gist 版本
+func (c *Cache) putRevision(sha string, body []byte) {
+	if !shaPattern.MatchString(sha) {
+		return
+	}
+	writeFileAtomic(c.revisionPath(sha), body)
+}
+
+// CacheStats 缓存统计
+type CacheStats struct {
+	Responses int   // 条件请求缓存的响应数
+	Revisions int   // Gist 版本数
+	Bytes     int64 // 占用的磁盘空间
+}
+
+// Stats 统计缓存内容
+func (c *Cache) Stats() (CacheStats, error) {
+	var stats CacheStats
+	err := filepath.Walk(c.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		stats.Bytes += info.Size()
+		rel, _ := filepath.Rel(c.dir, path)
+		switch strings.SplitN(filepath.ToSlash(rel), "/", 2)[0] {
+		case "http":
+			stats.Responses++
+		case "gists":
+			stats.Revisions++
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Clear 删除全部缓存
+func (c *Cache) Clear() error {
+	for _, sub := range []string{"http", "gists"} {
+		if err := os.RemoveAll(filepath.Join(c.dir, sub)); err != nil {
+			return fmt.Errorf("清除缓存失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免并发读到不完整的内容
+func writeFileAtomic(path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	f.Close()
+	if err != nil {
+		os.Remove(f.Name())
+		return
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+	}
+}
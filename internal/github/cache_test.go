@@ -0,0 +1,51 @@
+package github
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClientCacheRevalidates(t *testing.T) {
+	srv := newIssueServer(t, 3)
+	cache := NewCache(t.TempDir())
+	var traces []RequestTrace
+	client := NewClientWithBaseURL("token", srv.URL).WithCache(cache).WithTracer(func(tr RequestTrace) {
+		traces = append(traces, tr)
+	})
+
+	first, err := client.ListIssues("o", "r", nil, "open", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.ListIssues("o", "r", nil, "open", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(traces) != 2 {
+		t.Fatalf("期望 2 次请求, 得到 %d", len(traces))
+	}
+	if got := traces[0].RequestHeaders["If-None-Match"]; got != "" {
+		t.Errorf("第一次请求不应带 If-None-Match, 得到 %q", got)
+	}
+	if got := traces[1].RequestHeaders["If-None-Match"]; got != `"v1"` {
+		t.Errorf("第二次请求应带缓存的 ETag, 得到 %q", got)
+	}
+	if traces[1].Status != http.StatusNotModified {
+		t.Errorf("第二次请求状态 = %d, 期望 304", traces[1].Status)
+	}
+	if traces[0].RequestHeaders["Authorization"] != "***" {
+		t.Errorf("跟踪中的 Authorization 应脱敏, 得到 %q", traces[0].RequestHeaders["Authorization"])
+	}
+	if len(second) != len(first) || len(second) != 3 {
+		t.Errorf("304 时应返回缓存内容: 第一次 %v, 第二次 %v", issueNumbers(first), issueNumbers(second))
+	}
+
+	// 缓存按 token 隔离
+	if cache.get("token", traces[0].URL) == nil {
+		t.Error("响应应写入缓存")
+	}
+	if cache.get("other-token", traces[0].URL) != nil {
+		t.Error("不同 token 不应命中同一缓存")
+	}
+}
@@ -2,36 +2,122 @@ package github
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
 const (
-	baseURL    = "https://api.github.com"
+	// DefaultBaseURL github.com 的 API 地址
+	DefaultBaseURL = "https://api.github.com"
+
 	apiVersion = "2022-11-28"
+
+	// maxPerPage GitHub 列表接口单页最大条数
+	maxPerPage = 100
 )
 
+// APIError GitHub API 返回的错误响应
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API 错误 (%d): %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound 判断错误是否为 404
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
 // Client GitHub API 客户端
 type Client struct {
 	token      string
+	baseURL    string
 	httpClient *http.Client
+	ctx        context.Context
+	onPage     func(fetched int)
+	tracer     func(RequestTrace)
+	cache      *Cache
 }
 
 // NewClient 创建新的 GitHub 客户端
 func NewClient(token string) *Client {
+	return NewClientWithBaseURL(token, DefaultBaseURL)
+}
+
+// NewClientWithBaseURL 创建指定 API 地址的 GitHub 客户端（用于 GitHub Enterprise）
+func NewClientWithBaseURL(token, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
 	return &Client{
-		token: token,
+		token:   token,
+		baseURL: strings.TrimRight(baseURL, "/"),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 }
 
+// WithContext 返回使用指定 context 的客户端副本，context 取消时中断进行中的请求
+func (c *Client) WithContext(ctx context.Context) *Client {
+	cp := *c
+	cp.ctx = ctx
+	return &cp
+}
+
+// WithPageHook 返回设置了翻页回调的客户端副本，列表接口每获取一页调用一次
+func (c *Client) WithPageHook(fn func(fetched int)) *Client {
+	cp := *c
+	cp.onPage = fn
+	return &cp
+}
+
+// WithCache 返回使用磁盘缓存的客户端副本，cache 为 nil 时不使用缓存
+func (c *Client) WithCache(cache *Cache) *Client {
+	cp := *c
+	cp.cache = cache
+	return &cp
+}
+
+// pageFetched 通知翻页回调
+func (c *Client) pageFetched(fetched int) {
+	if c.onPage != nil {
+		c.onPage(fetched)
+	}
+}
+
+// response API 响应
+type response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
 // doRequest 执行 HTTP 请求
 func (c *Client) doRequest(method, url string, body interface{}) ([]byte, error) {
+	resp, err := c.do(method, url, body, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// do 执行 HTTP 请求并返回状态码和响应头，header 为附加的请求头
+//
+// 4xx/5xx 响应返回 *APIError；304 Not Modified 作为正常响应返回。
+// 设置了缓存时，GET 请求自动附加条件请求头，304 时返回缓存的内容；
+// 调用方自行指定 If-None-Match 时不使用缓存。
+func (c *Client) do(method, url string, body interface{}, header http.Header) (result *response, err error) {
 	var bodyReader io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
@@ -41,7 +127,12 @@ func (c *Client) doRequest(method, url string, body interface{}) ([]byte, error)
 		bodyReader = bytes.NewReader(jsonBody)
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -52,8 +143,45 @@ func (c *Client) doRequest(method, url string, body interface{}) ([]byte, error)
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	for name, values := range header {
+		for _, v := range values {
+			req.Header.Add(name, v)
+		}
+	}
+
+	var cached *cacheEntry
+	useCache := c.cache != nil && method == http.MethodGet && header.Get("If-None-Match") == ""
+	if useCache {
+		if cached = c.cache.get(c.token, url); cached != nil {
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
+	}
 
-	resp, err := c.httpClient.Do(req)
+	var resp *http.Response
+	if c.tracer != nil {
+		start := time.Now()
+		defer func() {
+			trace := RequestTrace{
+				Method:         method,
+				URL:            url,
+				Duration:       time.Since(start),
+				RequestHeaders: redactHeaders(req.Header),
+				Err:            err,
+			}
+			if resp != nil {
+				trace.Status = resp.StatusCode
+				trace.RateLimitRemaining = resp.Header.Get("X-RateLimit-Remaining")
+			}
+			c.tracer(trace)
+		}()
+	}
+
+	resp, err = c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("请求失败: %w", err)
 	}
@@ -65,10 +193,20 @@ func (c *Client) doRequest(method, url string, body interface{}) ([]byte, error)
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API 错误 (%d): %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	if useCache {
+		if resp.StatusCode == http.StatusNotModified && cached != nil {
+			return &response{StatusCode: http.StatusOK, Header: resp.Header, Body: cached.Body}, nil
+		}
+		etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		if resp.StatusCode == http.StatusOK && (etag != "" || lastModified != "") {
+			c.cache.put(c.token, &cacheEntry{URL: url, ETag: etag, LastModified: lastModified, Body: respBody})
+		}
 	}
 
-	return respBody, nil
+	return &response{StatusCode: resp.StatusCode, Header: resp.Header, Body: respBody}, nil
 }
 
 // Get 发送 GET 请求
@@ -85,3 +223,9 @@ func (c *Client) Post(url string, body interface{}) ([]byte, error) {
 func (c *Client) Patch(url string, body interface{}) ([]byte, error) {
 	return c.doRequest(http.MethodPatch, url, body)
 }
+
+// Delete 发送 DELETE 请求
+func (c *Client) Delete(url string) error {
+	_, err := c.doRequest(http.MethodDelete, url, nil)
+	return err
+}
@@ -0,0 +1,140 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// issueServer 模拟 GitHub 的 Issue 列表接口：按 page/per_page 分页返回 total 个 Issue，
+// 第一页带 ETag，请求头 If-None-Match 与 ETag 相同时返回 304
+type issueServer struct {
+	*httptest.Server
+	total int
+	etag  string
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func newIssueServer(t *testing.T, total int) *issueServer {
+	s := &issueServer{total: total, etag: `"v1"`}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *issueServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	etag := s.etag
+	s.mu.Unlock()
+
+	if r.URL.Path != "/repos/o/r/issues" {
+		http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer token" {
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if page == 1 {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+	}
+
+	issues := []Issue{}
+	for n := (page-1)*perPage + 1; n <= page*perPage && n <= s.total; n++ {
+		issues = append(issues, Issue{Number: n, Title: "issue " + strconv.Itoa(n)})
+	}
+	json.NewEncoder(w).Encode(issues)
+}
+
+// pages 返回已收到的请求的 page 参数
+func (s *issueServer) pages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pages := make([]string, len(s.requests))
+	for i, r := range s.requests {
+		pages[i] = r.URL.Query().Get("page")
+	}
+	return pages
+}
+
+func (s *issueServer) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+func issueNumbers(issues []Issue) []int {
+	numbers := make([]int, len(issues))
+	for i, issue := range issues {
+		numbers[i] = issue.Number
+	}
+	return numbers
+}
+
+func TestListIssuesPagination(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		limit     int
+		wantCount int
+		wantPages int
+	}{
+		{name: "单页", total: 30, limit: 0, wantCount: 30, wantPages: 1},
+		{name: "恰好一整页时再取一页确认结束", total: 100, limit: 0, wantCount: 100, wantPages: 2},
+		{name: "多页", total: 250, limit: 0, wantCount: 250, wantPages: 3},
+		{name: "limit 小于单页上限", total: 250, limit: 10, wantCount: 10, wantPages: 1},
+		{name: "limit 跨页时截断", total: 250, limit: 150, wantCount: 150, wantPages: 2},
+	}
+	for _, tt := range tests {
+		srv := newIssueServer(t, tt.total)
+		var fetched []int
+		client := NewClientWithBaseURL("token", srv.URL).WithPageHook(func(n int) { fetched = append(fetched, n) })
+
+		issues, err := client.ListIssues("o", "r", []string{"pending"}, "open", tt.limit)
+		if err != nil {
+			t.Errorf("%s: ListIssues 报错: %v", tt.name, err)
+			continue
+		}
+		if len(issues) != tt.wantCount {
+			t.Errorf("%s: 返回 %d 个 Issue, 期望 %d", tt.name, len(issues), tt.wantCount)
+		}
+		if pages := srv.pages(); len(pages) != tt.wantPages {
+			t.Errorf("%s: 请求了页 %v, 期望 %d 页", tt.name, pages, tt.wantPages)
+		}
+		if len(fetched) != tt.wantPages {
+			t.Errorf("%s: 翻页回调 %v, 期望调用 %d 次", tt.name, fetched, tt.wantPages)
+		}
+		if numbers := issueNumbers(issues); len(numbers) > 0 && (numbers[0] != 1 || numbers[len(numbers)-1] != tt.wantCount) {
+			t.Errorf("%s: Issue 编号范围 %d..%d, 期望 1..%d", tt.name, numbers[0], numbers[len(numbers)-1], tt.wantCount)
+		}
+	}
+}
+
+func TestListIssuesAPIError(t *testing.T) {
+	srv := newIssueServer(t, 1)
+	_, err := NewClientWithBaseURL("wrong", srv.URL).ListIssues("o", "r", nil, "", 0)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("期望 401 APIError, 得到 %v", err)
+	}
+
+	_, err = NewClientWithBaseURL("token", srv.URL).ListIssues("o", "missing", nil, "", 0)
+	if !IsNotFound(err) {
+		t.Fatalf("期望 404, 得到 %v", err)
+	}
+}
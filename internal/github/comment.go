@@ -0,0 +1,42 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Comment Issue 评论
+type Comment struct {
+	ID                int64  `json:"id"`
+	Body              string `json:"body"`
+	User              User   `json:"user"`
+	HTMLURL           string `json:"html_url"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
+	AuthorAssociation string `json:"author_association,omitempty"`
+}
+
+// ListComments 列出 Issue 的全部评论（按创建时间升序，自动翻页）
+func (c *Client) ListComments(owner, repo string, number int) ([]Comment, error) {
+	var all []Comment
+	for page := 1; ; page++ {
+		apiURL := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments?per_page=%d&page=%d", c.baseURL, owner, repo, number, maxPerPage, page)
+		respBody, err := c.Get(apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("列出评论失败: %w", err)
+		}
+
+		var comments []Comment
+		if err := json.Unmarshal(respBody, &comments); err != nil {
+			return nil, fmt.Errorf("解析评论列表失败: %w", err)
+		}
+
+		all = append(all, comments...)
+		c.pageFetched(len(all))
+		if len(comments) < maxPerPage {
+			break
+		}
+	}
+
+	return all, nil
+}
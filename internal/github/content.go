@@ -0,0 +1,60 @@
+package github
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ContentEntry 仓库文件或目录条目
+type ContentEntry struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Type     string `json:"type"` // file / dir
+	SHA      string `json:"sha"`
+	Content  string `json:"content,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// ListDirectory 列出仓库目录下的条目
+func (c *Client) ListDirectory(owner, repo, path string) ([]ContentEntry, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/contents/%s", c.baseURL, owner, repo, strings.Trim(path, "/"))
+	respBody, err := c.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("列出目录失败: %w", err)
+	}
+
+	var entries []ContentEntry
+	if err := json.Unmarshal(respBody, &entries); err != nil {
+		return nil, fmt.Errorf("解析目录列表失败: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetFileContent 获取仓库中文件的内容
+func (c *Client) GetFileContent(owner, repo, path string) ([]byte, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/contents/%s", c.baseURL, owner, repo, strings.Trim(path, "/"))
+	respBody, err := c.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("获取文件失败: %w", err)
+	}
+
+	var entry ContentEntry
+	if err := json.Unmarshal(respBody, &entry); err != nil {
+		return nil, fmt.Errorf("解析文件响应失败: %w", err)
+	}
+	if entry.Type != "file" {
+		return nil, fmt.Errorf("%s 不是文件", path)
+	}
+	if entry.Encoding != "base64" {
+		return []byte(entry.Content), nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(entry.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("解码文件内容失败: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,40 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// IssueEvent Issue 事件（标签变更、关闭、重新打开等）
+type IssueEvent struct {
+	ID        int64  `json:"id"`
+	Event     string `json:"event"` // labeled, unlabeled, closed, reopened ...
+	Actor     User   `json:"actor"`
+	Label     *Label `json:"label,omitempty"` // labeled/unlabeled 事件的标签
+	CreatedAt string `json:"created_at"`
+}
+
+// ListIssueEvents 列出 Issue 的全部事件（按时间升序，自动翻页）
+func (c *Client) ListIssueEvents(owner, repo string, number int) ([]IssueEvent, error) {
+	var all []IssueEvent
+	for page := 1; ; page++ {
+		apiURL := fmt.Sprintf("%s/repos/%s/%s/issues/%d/events?per_page=%d&page=%d", c.baseURL, owner, repo, number, maxPerPage, page)
+		respBody, err := c.Get(apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("列出 Issue 事件失败: %w", err)
+		}
+
+		var events []IssueEvent
+		if err := json.Unmarshal(respBody, &events); err != nil {
+			return nil, fmt.Errorf("解析 Issue 事件失败: %w", err)
+		}
+
+		all = append(all, events...)
+		c.pageFetched(len(all))
+		if len(events) < maxPerPage {
+			break
+		}
+	}
+
+	return all, nil
+}
@@ -7,7 +7,9 @@ import (
 
 // GistFile Gist 文件
 type GistFile struct {
-	Content string `json:"content"`
+	Filename string `json:"filename,omitempty"`
+	Size     int    `json:"size,omitempty"`
+	Content  string `json:"content"`
 }
 
 // Gist Gist 数据结构
@@ -18,6 +20,14 @@ type Gist struct {
 	Files       map[string]GistFile `json:"files"`
 	HTMLURL     string              `json:"html_url,omitempty"`
 	CreatedAt   string              `json:"created_at,omitempty"`
+	UpdatedAt   string              `json:"updated_at,omitempty"`
+	History     []GistRevision      `json:"history,omitempty"`
+}
+
+// GistRevision Gist 的一个版本，最新的版本在前
+type GistRevision struct {
+	Version     string `json:"version"`
+	CommittedAt string `json:"committed_at,omitempty"`
 }
 
 // CreateGistRequest 创建 Gist 请求
@@ -40,7 +50,7 @@ func (c *Client) CreateGist(description string, public bool, files map[string]st
 		Files:       gistFiles,
 	}
 
-	respBody, err := c.Post(baseURL+"/gists", req)
+	respBody, err := c.Post(c.baseURL+"/gists", req)
 	if err != nil {
 		return nil, fmt.Errorf("创建 Gist 失败: %w", err)
 	}
@@ -55,7 +65,7 @@ func (c *Client) CreateGist(description string, public bool, files map[string]st
 
 // GetGist 获取 Gist
 func (c *Client) GetGist(gistID string) (*Gist, error) {
-	respBody, err := c.Get(baseURL + "/gists/" + gistID)
+	respBody, err := c.Get(c.baseURL + "/gists/" + gistID)
 	if err != nil {
 		return nil, fmt.Errorf("获取 Gist 失败: %w", err)
 	}
@@ -65,5 +75,93 @@ func (c *Client) GetGist(gistID string) (*Gist, error) {
 		return nil, fmt.Errorf("解析 Gist 响应失败: %w", err)
 	}
 
+	// 最新版本同时存入版本缓存
+	if c.cache != nil && len(gist.History) > 0 {
+		c.cache.putRevision(gist.History[0].Version, respBody)
+	}
+
+	return &gist, nil
+}
+
+// GetGistRevision 获取 Gist 的指定版本，版本内容不可变，设置了缓存时优先读取缓存
+func (c *Client) GetGistRevision(gistID, sha string) (*Gist, error) {
+	var respBody []byte
+	if c.cache != nil {
+		respBody = c.cache.getRevision(sha)
+	}
+	if respBody == nil {
+		var err error
+		respBody, err = c.Get(c.baseURL + "/gists/" + gistID + "/" + sha)
+		if err != nil {
+			return nil, fmt.Errorf("获取 Gist 版本失败: %w", err)
+		}
+		if c.cache != nil {
+			c.cache.putRevision(sha, respBody)
+		}
+	}
+
+	var gist Gist
+	if err := json.Unmarshal(respBody, &gist); err != nil {
+		return nil, fmt.Errorf("解析 Gist 响应失败: %w", err)
+	}
+	return &gist, nil
+}
+
+// UpdateGist 更新 Gist 中的文件，生成新版本
+//
+// files 中的文件被新建或覆盖，remove 中的文件被删除，未列出的文件保持不变。
+func (c *Client) UpdateGist(gistID string, files map[string]string, remove []string) (*Gist, error) {
+	gistFiles := make(map[string]interface{}, len(files)+len(remove))
+	for name, content := range files {
+		gistFiles[name] = GistFile{Content: content}
+	}
+	for _, name := range remove {
+		gistFiles[name] = nil
+	}
+
+	respBody, err := c.Patch(c.baseURL+"/gists/"+gistID, map[string]interface{}{"files": gistFiles})
+	if err != nil {
+		return nil, fmt.Errorf("更新 Gist 失败: %w", err)
+	}
+
+	var gist Gist
+	if err := json.Unmarshal(respBody, &gist); err != nil {
+		return nil, fmt.Errorf("解析 Gist 响应失败: %w", err)
+	}
 	return &gist, nil
 }
+
+// ListGists 列出当前用户的全部 Gist（自动翻页）
+//
+// 列表接口返回的文件不包含内容，需要时请再调用 GetGist。
+func (c *Client) ListGists() ([]Gist, error) {
+	var all []Gist
+	for page := 1; ; page++ {
+		apiURL := fmt.Sprintf("%s/gists?per_page=%d&page=%d", c.baseURL, maxPerPage, page)
+		respBody, err := c.Get(apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("列出 Gist 失败: %w", err)
+		}
+
+		var gists []Gist
+		if err := json.Unmarshal(respBody, &gists); err != nil {
+			return nil, fmt.Errorf("解析 Gist 列表失败: %w", err)
+		}
+
+		all = append(all, gists...)
+		c.pageFetched(len(all))
+		if len(gists) < maxPerPage {
+			break
+		}
+	}
+
+	return all, nil
+}
+
+// DeleteGist 删除 Gist
+func (c *Client) DeleteGist(gistID string) error {
+	if err := c.Delete(c.baseURL + "/gists/" + gistID); err != nil {
+		return fmt.Errorf("删除 Gist 失败: %w", err)
+	}
+	return nil
+}
@@ -3,21 +3,24 @@ package github
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
 )
 
 // Issue GitHub Issue 数据结构
 type Issue struct {
-	Number    int      `json:"number"`
-	Title     string   `json:"title"`
-	Body      string   `json:"body"`
-	State     string   `json:"state"`
-	HTMLURL   string   `json:"html_url"`
-	Labels    []Label  `json:"labels"`
-	CreatedAt string   `json:"created_at"`
-	UpdatedAt string   `json:"updated_at"`
-	User      User     `json:"user"`
+	Number    int     `json:"number"`
+	Title     string  `json:"title"`
+	Body      string  `json:"body"`
+	State     string  `json:"state"`
+	HTMLURL   string  `json:"html_url"`
+	Labels    []Label `json:"labels"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+	ClosedAt  string  `json:"closed_at,omitempty"`
+	User      User    `json:"user"`
+	Comments  int     `json:"comments"` // 评论数
 }
 
 // Label 标签
@@ -42,6 +45,7 @@ type CreateIssueRequest struct {
 type UpdateIssueRequest struct {
 	State  string   `json:"state,omitempty"`
 	Labels []string `json:"labels,omitempty"`
+	Body   string   `json:"body,omitempty"`
 }
 
 // CreateIssue 创建 Issue
@@ -52,7 +56,7 @@ func (c *Client) CreateIssue(owner, repo, title, body string, labels []string) (
 		Labels: labels,
 	}
 
-	url := fmt.Sprintf("%s/repos/%s/%s/issues", baseURL, owner, repo)
+	url := fmt.Sprintf("%s/repos/%s/%s/issues", c.baseURL, owner, repo)
 	respBody, err := c.Post(url, req)
 	if err != nil {
 		return nil, fmt.Errorf("创建 Issue 失败: %w", err)
@@ -68,7 +72,7 @@ func (c *Client) CreateIssue(owner, repo, title, body string, labels []string) (
 
 // GetIssue 获取 Issue
 func (c *Client) GetIssue(owner, repo string, number int) (*Issue, error) {
-	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d", baseURL, owner, repo, number)
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d", c.baseURL, owner, repo, number)
 	respBody, err := c.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("获取 Issue 失败: %w", err)
@@ -83,6 +87,8 @@ func (c *Client) GetIssue(owner, repo string, number int) (*Issue, error) {
 }
 
 // ListIssues 列出 Issue
+//
+// limit 大于单页上限时自动翻页；limit <= 0 表示获取全部。
 func (c *Client) ListIssues(owner, repo string, labels []string, state string, limit int) ([]Issue, error) {
 	params := url.Values{}
 	if len(labels) > 0 {
@@ -91,22 +97,91 @@ func (c *Client) ListIssues(owner, repo string, labels []string, state string, l
 	if state != "" {
 		params.Set("state", state)
 	}
-	if limit > 0 {
-		params.Set("per_page", fmt.Sprintf("%d", limit))
-	}
 
-	apiURL := fmt.Sprintf("%s/repos/%s/%s/issues?%s", baseURL, owner, repo, params.Encode())
-	respBody, err := c.Get(apiURL)
-	if err != nil {
-		return nil, fmt.Errorf("列出 Issue 失败: %w", err)
+	perPage := maxPerPage
+	if limit > 0 && limit < maxPerPage {
+		perPage = limit
+	}
+	params.Set("per_page", fmt.Sprintf("%d", perPage))
+
+	var all []Issue
+	for page := 1; ; page++ {
+		params.Set("page", fmt.Sprintf("%d", page))
+		apiURL := fmt.Sprintf("%s/repos/%s/%s/issues?%s", c.baseURL, owner, repo, params.Encode())
+		respBody, err := c.Get(apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("列出 Issue 失败: %w", err)
+		}
+
+		var issues []Issue
+		if err := json.Unmarshal(respBody, &issues); err != nil {
+			return nil, fmt.Errorf("解析 Issue 列表失败: %w", err)
+		}
+
+		all = append(all, issues...)
+		c.pageFetched(len(all))
+		if len(issues) < perPage || (limit > 0 && len(all) >= limit) {
+			break
+		}
 	}
 
-	var issues []Issue
-	if err := json.Unmarshal(respBody, &issues); err != nil {
-		return nil, fmt.Errorf("解析 Issue 列表失败: %w", err)
+	if limit > 0 && len(all) > limit {
+		all = all[:limit]
 	}
 
-	return issues, nil
+	return all, nil
+}
+
+// ListIssuesSince 列出 since 之后更新过的 Issue（按更新时间升序，自动翻页）
+//
+// etag 为上次调用返回的 ETag，Issue 没有变化时 GitHub 返回 304，此时
+// notModified 为 true 且不消耗 API 配额。
+func (c *Client) ListIssuesSince(owner, repo string, labels []string, state, since, etag string) (issues []Issue, newETag string, notModified bool, err error) {
+	params := url.Values{}
+	if len(labels) > 0 {
+		params.Set("labels", strings.Join(labels, ","))
+	}
+	if state != "" {
+		params.Set("state", state)
+	}
+	if since != "" {
+		params.Set("since", since)
+	}
+	params.Set("sort", "updated")
+	params.Set("direction", "asc")
+	params.Set("per_page", fmt.Sprintf("%d", maxPerPage))
+
+	for page := 1; ; page++ {
+		params.Set("page", fmt.Sprintf("%d", page))
+		apiURL := fmt.Sprintf("%s/repos/%s/%s/issues?%s", c.baseURL, owner, repo, params.Encode())
+
+		// 只有第一页使用条件请求
+		var header http.Header
+		if page == 1 && etag != "" {
+			header = http.Header{"If-None-Match": {etag}}
+		}
+		resp, err := c.do(http.MethodGet, apiURL, nil, header)
+		if err != nil {
+			return nil, "", false, fmt.Errorf("列出 Issue 失败: %w", err)
+		}
+		if resp.StatusCode == http.StatusNotModified {
+			return nil, etag, true, nil
+		}
+		if page == 1 {
+			newETag = resp.Header.Get("ETag")
+		}
+
+		var pageIssues []Issue
+		if err := json.Unmarshal(resp.Body, &pageIssues); err != nil {
+			return nil, "", false, fmt.Errorf("解析 Issue 列表失败: %w", err)
+		}
+		issues = append(issues, pageIssues...)
+		c.pageFetched(len(issues))
+		if len(pageIssues) < maxPerPage {
+			break
+		}
+	}
+	return issues, newETag, false, nil
 }
 
 // UpdateIssue 更新 Issue
@@ -116,7 +191,7 @@ func (c *Client) UpdateIssue(owner, repo string, number int, state string, label
 		Labels: labels,
 	}
 
-	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d", baseURL, owner, repo, number)
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d", c.baseURL, owner, repo, number)
 	respBody, err := c.Patch(url, req)
 	if err != nil {
 		return nil, fmt.Errorf("更新 Issue 失败: %w", err)
@@ -133,7 +208,7 @@ func (c *Client) UpdateIssue(owner, repo string, number int, state string, label
 // AddComment 添加评论
 func (c *Client) AddComment(owner, repo string, number int, body string) error {
 	req := map[string]string{"body": body}
-	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", baseURL, owner, repo, number)
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", c.baseURL, owner, repo, number)
 	_, err := c.Post(url, req)
 	if err != nil {
 		return fmt.Errorf("添加评论失败: %w", err)
@@ -141,6 +216,15 @@ func (c *Client) AddComment(owner, repo string, number int, body string) error {
 	return nil
 }
 
+// UpdateIssueBody 更新 Issue 描述
+func (c *Client) UpdateIssueBody(owner, repo string, number int, body string) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d", c.baseURL, owner, repo, number)
+	if _, err := c.Patch(url, UpdateIssueRequest{Body: body}); err != nil {
+		return fmt.Errorf("更新 Issue 描述失败: %w", err)
+	}
+	return nil
+}
+
 // CloseIssue 关闭 Issue
 func (c *Client) CloseIssue(owner, repo string, number int) (*Issue, error) {
 	return c.UpdateIssue(owner, repo, number, "closed", nil)
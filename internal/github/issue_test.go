@@ -0,0 +1,61 @@
+package github
+
+import "testing"
+
+func TestListIssuesSince(t *testing.T) {
+	srv := newIssueServer(t, 120)
+	client := NewClientWithBaseURL("token", srv.URL)
+
+	issues, etag, notModified, err := client.ListIssuesSince("o", "r", []string{"pending"}, "all", "2024-01-01T00:00:00Z", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notModified || len(issues) != 120 || etag != `"v1"` {
+		t.Fatalf("首次调用 = %d 个 Issue, etag %q, notModified %v", len(issues), etag, notModified)
+	}
+	srv.mu.Lock()
+	query := srv.requests[0].URL.Query()
+	srv.mu.Unlock()
+	for key, want := range map[string]string{
+		"since": "2024-01-01T00:00:00Z", "sort": "updated", "direction": "asc",
+		"labels": "pending", "state": "all", "per_page": "100",
+	} {
+		if got := query.Get(key); got != want {
+			t.Errorf("参数 %s = %q, 期望 %q", key, got, want)
+		}
+	}
+
+	// 没有变化时返回 304，保留原 ETag
+	srv.reset()
+	issues, etag, notModified, err = client.ListIssuesSince("o", "r", nil, "", "2024-01-01T00:00:00Z", `"v1"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !notModified || len(issues) != 0 || etag != `"v1"` {
+		t.Errorf("未变化时 = %d 个 Issue, etag %q, notModified %v", len(issues), etag, notModified)
+	}
+	if pages := srv.pages(); len(pages) != 1 {
+		t.Errorf("未变化时只应请求第一页, 请求了 %v", pages)
+	}
+
+	// 有变化时返回新的 ETag，只有第一页使用条件请求
+	srv.mu.Lock()
+	srv.etag = `"v2"`
+	srv.mu.Unlock()
+	srv.reset()
+	issues, etag, notModified, err = client.ListIssuesSince("o", "r", nil, "", "", `"v1"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notModified || len(issues) != 120 || etag != `"v2"` {
+		t.Errorf("变化后 = %d 个 Issue, etag %q, notModified %v", len(issues), etag, notModified)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if len(srv.requests) != 2 {
+		t.Fatalf("期望请求 2 页, 得到 %d", len(srv.requests))
+	}
+	if got := srv.requests[1].Header.Get("If-None-Match"); got != "" {
+		t.Errorf("第二页不应使用条件请求, 得到 %q", got)
+	}
+}
@@ -0,0 +1,60 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CreateLabelRequest 创建标签请求
+type CreateLabelRequest struct {
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ListLabels 列出仓库的全部标签（自动翻页）
+func (c *Client) ListLabels(owner, repo string) ([]Label, error) {
+	var all []Label
+	for page := 1; ; page++ {
+		apiURL := fmt.Sprintf("%s/repos/%s/%s/labels?per_page=%d&page=%d", c.baseURL, owner, repo, maxPerPage, page)
+		respBody, err := c.Get(apiURL)
+		if err != nil {
+			return nil, fmt.Errorf("列出标签失败: %w", err)
+		}
+
+		var labels []Label
+		if err := json.Unmarshal(respBody, &labels); err != nil {
+			return nil, fmt.Errorf("解析标签列表失败: %w", err)
+		}
+
+		all = append(all, labels...)
+		c.pageFetched(len(all))
+		if len(labels) < maxPerPage {
+			break
+		}
+	}
+
+	return all, nil
+}
+
+// CreateLabel 创建标签
+func (c *Client) CreateLabel(owner, repo, name, color, description string) (*Label, error) {
+	req := CreateLabelRequest{
+		Name:        name,
+		Color:       color,
+		Description: description,
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/%s/labels", c.baseURL, owner, repo)
+	respBody, err := c.Post(url, req)
+	if err != nil {
+		return nil, fmt.Errorf("创建标签失败: %w", err)
+	}
+
+	var label Label
+	if err := json.Unmarshal(respBody, &label); err != nil {
+		return nil, fmt.Errorf("解析标签响应失败: %w", err)
+	}
+
+	return &label, nil
+}
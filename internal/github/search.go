@@ -0,0 +1,66 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// maxSearchResults GitHub 搜索接口最多返回的结果数
+const maxSearchResults = 1000
+
+// searchIssuesResponse 搜索接口响应
+type searchIssuesResponse struct {
+	TotalCount int     `json:"total_count"`
+	Items      []Issue `json:"items"`
+}
+
+// SearchIssues 使用 GitHub 搜索语法搜索 Issue
+//
+// limit 大于单页上限时自动翻页；limit <= 0 表示获取全部（GitHub 最多返回 1000 条）。
+func (c *Client) SearchIssues(query string, limit int) ([]Issue, error) {
+	issues, _, err := c.SearchIssuesWithTotal(query, limit)
+	return issues, err
+}
+
+// SearchIssuesWithTotal 同 SearchIssues，同时返回匹配总数 (total_count)
+//
+// 匹配超过 1000 条时 total 大于返回的数量，调用方据此判断结果是否完整。
+func (c *Client) SearchIssuesWithTotal(query string, limit int) (issues []Issue, total int, err error) {
+	perPage := maxPerPage
+	if limit > 0 && limit < maxPerPage {
+		perPage = limit
+	}
+
+	var all []Issue
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("q", query)
+		params.Set("per_page", fmt.Sprintf("%d", perPage))
+		params.Set("page", fmt.Sprintf("%d", page))
+		apiURL := fmt.Sprintf("%s/search/issues?%s", c.baseURL, params.Encode())
+		respBody, err := c.Get(apiURL)
+		if err != nil {
+			return nil, 0, fmt.Errorf("搜索 Issue 失败: %w", err)
+		}
+
+		var result searchIssuesResponse
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return nil, 0, fmt.Errorf("解析搜索结果失败: %w", err)
+		}
+		total = result.TotalCount
+
+		all = append(all, result.Items...)
+		c.pageFetched(len(all))
+		if len(result.Items) < perPage || len(all) >= result.TotalCount || len(all) >= maxSearchResults ||
+			(limit > 0 && len(all) >= limit) {
+			break
+		}
+	}
+
+	if limit > 0 && len(all) > limit {
+		all = all[:limit]
+	}
+
+	return all, total, nil
+}
@@ -0,0 +1,87 @@
+package github
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RequestTrace 一次 API 调用的跟踪信息，用于诊断日志
+type RequestTrace struct {
+	Method             string
+	URL                string
+	Status             int // 请求未发出或未收到响应时为 0
+	Duration           time.Duration
+	RequestHeaders     map[string]string // Authorization 已脱敏
+	RateLimitRemaining string
+	Err                error
+}
+
+// WithTracer 返回设置了跟踪回调的客户端副本，每次 API 调用结束后调用一次
+func (c *Client) WithTracer(fn func(RequestTrace)) *Client {
+	cp := *c
+	cp.tracer = fn
+	return &cp
+}
+
+// Endpoint 返回去掉仓库、编号、标签名和 ID 后的请求路径，如 /repos/{owner}/{repo}/issues/{number}/comments，
+// 用于按接口聚合统计，避免统计标签取值无限增长
+func (t RequestTrace) Endpoint() string {
+	u, err := url.Parse(t.URL)
+	if err != nil {
+		return "unknown"
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	// GitHub Enterprise 的 API 位于 /api/v3 下，去掉前缀后与 github.com 聚合到同一接口
+	if len(parts) >= 2 && parts[0] == "api" && parts[1] == "v3" {
+		parts = parts[2:]
+	}
+	switch {
+	case len(parts) >= 3 && parts[0] == "repos":
+		parts[1], parts[2] = "{owner}", "{repo}"
+		// contents 之后是文件路径，labels 之后是标签名（可能含编码后的 /）
+		if len(parts) > 4 && parts[3] == "contents" {
+			parts = append(parts[:4], "{path}")
+		}
+		for i := 3; i < len(parts)-1; i++ {
+			if parts[i] == "labels" {
+				parts = append(parts[:i+1], "{name}")
+				break
+			}
+		}
+	case len(parts) >= 2 && parts[0] == "gists":
+		parts[1] = "{id}"
+		// 只有版本 SHA 才替换，/gists/{id}/comments 等子资源保持原样
+		if len(parts) >= 3 && isSHA(parts[2]) {
+			parts[2] = "{sha}"
+		}
+	}
+	for i, p := range parts {
+		if p != "" && strings.Trim(p, "0123456789") == "" {
+			parts[i] = "{number}"
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// isSHA 判断路径段是否为 40 位十六进制的提交 SHA
+func isSHA(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	return strings.Trim(strings.ToLower(s), "0123456789abcdef") == ""
+}
+
+// redactHeaders 复制请求头并隐藏凭据
+func redactHeaders(h http.Header) map[string]string {
+	headers := make(map[string]string, len(h))
+	for name := range h {
+		value := h.Get(name)
+		if name == "Authorization" && value != "" {
+			value = "***"
+		}
+		headers[name] = value
+	}
+	return headers
+}
@@ -0,0 +1,35 @@
+package github
+
+import "testing"
+
+func TestRequestTraceEndpoint(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://api.github.com/repos/o/r/issues?state=open&page=2", "/repos/{owner}/{repo}/issues"},
+		{"https://api.github.com/repos/o/r/issues/12", "/repos/{owner}/{repo}/issues/{number}"},
+		{"https://api.github.com/repos/o/r/issues/12/comments", "/repos/{owner}/{repo}/issues/{number}/comments"},
+		{"https://api.github.com/repos/o/r/contents/.github/issue-pack/types/a.json", "/repos/{owner}/{repo}/contents/{path}"},
+		{"https://api.github.com/repos/o/r/labels", "/repos/{owner}/{repo}/labels"},
+		{"https://api.github.com/repos/o/r/labels/needs-info", "/repos/{owner}/{repo}/labels/{name}"},
+		{"https://api.github.com/repos/o/r/labels/gip%3Astatus%2Fpending", "/repos/{owner}/{repo}/labels/{name}"},
+		{"https://api.github.com/repos/o/r/labels/2024", "/repos/{owner}/{repo}/labels/{name}"},
+		{"https://api.github.com/repos/o/r/issues/3/labels/bug", "/repos/{owner}/{repo}/issues/{number}/labels/{name}"},
+		{"https://api.github.com/gists", "/gists"},
+		{"https://api.github.com/gists/aa5a315d61ae9438b18d", "/gists/{id}"},
+		{"https://api.github.com/gists/aa5a315d61ae9438b18d/" + sha, "/gists/{id}/{sha}"},
+		{"https://api.github.com/gists/aa5a315d61ae9438b18d/comments", "/gists/{id}/comments"},
+		{"https://api.github.com/gists/aa5a315d61ae9438b18d/comments/42", "/gists/{id}/comments/{number}"},
+		{"https://ghe.example.com/api/v3/search/issues?q=x", "/search/issues"},
+		{"https://ghe.example.com/api/v3/repos/o/r/issues/7", "/repos/{owner}/{repo}/issues/{number}"},
+		{"://bad", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := (RequestTrace{URL: tt.url}).Endpoint(); got != tt.want {
+			t.Errorf("Endpoint(%q) = %q, 期望 %q", tt.url, got, tt.want)
+		}
+	}
+}
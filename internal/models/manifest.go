@@ -0,0 +1,144 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Manifest 目标仓库的 Issue 包接收声明
+//
+// 存放在目标仓库的 .github/issue-pack/manifest.json 中，描述仓库接受哪些
+// 类型的包、需要哪些字段以及处理时效等。
+type Manifest struct {
+	Schema         string              `json:"$schema,omitempty"`
+	Description    string              `json:"description,omitempty"`
+	AcceptedTypes  []string            `json:"accepted_types,omitempty"`  // 为空表示接受全部类型
+	SchemaVersions []string            `json:"schema_versions,omitempty"` // 为空表示只要求当前版本
+	RequiredFields map[string][]string `json:"required_fields,omitempty"` // 类型 → payload 必需字段（支持 a.b 路径）
+	TrustedSenders []string            `json:"trusted_senders,omitempty"` // 可信发送方（GitHub 用户或 owner/repo）
+	EncryptionKeys []EncryptionKey     `json:"encryption_keys,omitempty"`
+	Storage        []string            `json:"storage,omitempty"` // 接受的存储后端，为空表示 gist
+	SLA            *SLA                `json:"sla,omitempty"`
+	Contact        string              `json:"contact,omitempty"`
+}
+
+// EncryptionKey 接收方公布的加密公钥
+type EncryptionKey struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"` // 如 age、pgp
+	PublicKey string `json:"public_key"`
+}
+
+// SLA 处理时效承诺
+type SLA struct {
+	FirstResponse string `json:"first_response,omitempty"` // 如 "48h"、"3d"
+	Resolution    string `json:"resolution,omitempty"`
+}
+
+// ParseManifest 解析仓库 manifest
+func ParseManifest(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("解析 manifest 失败: %w", err)
+	}
+	return &m, nil
+}
+
+// Accepts 判断是否接受指定类型
+func (m *Manifest) Accepts(t IssueType) bool {
+	if len(m.AcceptedTypes) == 0 {
+		return true
+	}
+	for _, accepted := range m.AcceptedTypes {
+		if accepted == string(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsSchemaVersion 判断是否接受指定的包格式版本
+func (m *Manifest) AcceptsSchemaVersion(version string) bool {
+	if len(m.SchemaVersions) == 0 {
+		return version == SchemaVersion
+	}
+	for _, v := range m.SchemaVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsStorage 判断是否接受指定的存储后端
+func (m *Manifest) AcceptsStorage(storage string) bool {
+	if len(m.Storage) == 0 {
+		return storage == "gist"
+	}
+	for _, s := range m.Storage {
+		if s == storage {
+			return true
+		}
+	}
+	return false
+}
+
+// Check 检查 Issue 包是否满足 manifest 的要求
+func (m *Manifest) Check(t IssueType, payload interface{}) error {
+	if !m.Accepts(t) {
+		return fmt.Errorf("目标仓库不接受 %s 类型的 Issue (接受: %s)", t, strings.Join(m.AcceptedTypes, ", "))
+	}
+	if !m.AcceptsSchemaVersion(SchemaVersion) {
+		return fmt.Errorf("目标仓库不接受包格式 %s (接受: %s)", SchemaVersion, strings.Join(m.SchemaVersions, ", "))
+	}
+
+	required := m.RequiredFields[string(t)]
+	if len(required) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化 payload 失败: %w", err)
+	}
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("解析 payload 失败: %w", err)
+	}
+
+	var missing []string
+	for _, field := range required {
+		if !hasField(data, field) {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("payload 缺少目标仓库要求的字段: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// hasField 判断数据中是否存在非空字段，path 以 . 分隔
+func hasField(data interface{}, path string) bool {
+	current := data
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		current, ok = obj[key]
+		if !ok {
+			return false
+		}
+	}
+	switch v := current.(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestManifestCheck(t *testing.T) {
+	payload := map[string]interface{}{
+		"title":       "登录失败",
+		"description": "",
+		"tags":        []interface{}{},
+		"environment": map[string]interface{}{"os": "linux", "version": nil},
+	}
+
+	tests := []struct {
+		name     string
+		manifest Manifest
+		issue    IssueType
+		wantErr  string
+	}{
+		{
+			name:     "空 manifest 接受全部",
+			manifest: Manifest{},
+			issue:    TypeBugReport,
+		},
+		{
+			name:     "不接受的类型",
+			manifest: Manifest{AcceptedTypes: []string{"feature-request"}},
+			issue:    TypeBugReport,
+			wantErr:  "不接受 bug-report 类型",
+		},
+		{
+			name:     "不接受的包格式",
+			manifest: Manifest{SchemaVersions: []string{"0.1"}},
+			issue:    TypeBugReport,
+			wantErr:  "不接受包格式",
+		},
+		{
+			name:     "必需字段齐全",
+			manifest: Manifest{RequiredFields: map[string][]string{"bug-report": {"title", "environment.os"}}},
+			issue:    TypeBugReport,
+		},
+		{
+			name:     "其他类型的必需字段不生效",
+			manifest: Manifest{RequiredFields: map[string][]string{"feature-request": {"missing"}}},
+			issue:    TypeBugReport,
+		},
+		{
+			name: "空值视为缺少",
+			manifest: Manifest{RequiredFields: map[string][]string{
+				"bug-report": {"description", "tags", "environment.version", "environment.arch", "title.sub"},
+			}},
+			issue:   TypeBugReport,
+			wantErr: "description, tags, environment.version, environment.arch, title.sub",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.manifest.Check(tt.issue, payload)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: Check 报错: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Check = %v, 期望包含 %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
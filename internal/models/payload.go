@@ -7,6 +7,9 @@ import (
 
 const SchemaVersion = "cursortoolset-issue-v1"
 
+// ToolVersion 写入 meta.github_issue_version 的工具版本，由 CLI 设置
+var ToolVersion = "0.1.0"
+
 // IssueType Issue 类型
 type IssueType string
 
@@ -19,22 +22,38 @@ const (
 	TypeCustom         IssueType = "custom"
 )
 
+// BuiltinTypes 内置的 Issue 类型
+var BuiltinTypes = []IssueType{
+	TypeFeatureRequest,
+	TypeBugReport,
+	TypePackRegister,
+	TypePackSync,
+	TypeQuestion,
+	TypeCustom,
+}
+
 // IssuePackage Issue 包的完整结构（存储在 Gist 中）
 type IssuePackage struct {
-	Schema      string            `json:"$schema"`
-	Meta        Meta              `json:"meta"`
-	Type        IssueType         `json:"type"`
-	Target      Target            `json:"target"`
-	Payload     json.RawMessage   `json:"payload"`
-	Attachments []Attachment      `json:"attachments,omitempty"`
+	Schema      string          `json:"$schema"`
+	Meta        Meta            `json:"meta"`
+	Type        IssueType       `json:"type"`
+	Target      Target          `json:"target"`
+	Payload     json.RawMessage `json:"payload"`
+	Attachments []Attachment    `json:"attachments,omitempty"`
 }
 
 // Meta 元数据
 type Meta struct {
-	CreatedAt           string `json:"created_at"`
-	SourceProject       string `json:"source_project,omitempty"`
+	CreatedAt            string `json:"created_at"`
+	UpdatedAt            string `json:"updated_at,omitempty"` // 最近一次 amend 的时间
+	SourceProject        string `json:"source_project,omitempty"`
+	SourceCommit         string `json:"source_commit,omitempty"`
+	SourceBranch         string `json:"source_branch,omitempty"`
+	SourcePack           string `json:"source_pack,omitempty"`
+	SourcePackVersion    string `json:"source_pack_version,omitempty"`
+	Platform             string `json:"platform,omitempty"`
 	CursorToolsetVersion string `json:"cursortoolset_version,omitempty"`
-	GitHubIssueVersion  string `json:"github_issue_version"`
+	GitHubIssueVersion   string `json:"github_issue_version"`
 }
 
 // Target 目标信息
@@ -102,7 +121,7 @@ func NewIssuePackage(issueType IssueType, targetRepo string, payload interface{}
 		Schema: SchemaVersion,
 		Meta: Meta{
 			CreatedAt:          time.Now().UTC().Format(time.RFC3339),
-			GitHubIssueVersion: "0.1.0",
+			GitHubIssueVersion: ToolVersion,
 		},
 		Type: issueType,
 		Target: Target{
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TypeDefinition 目标仓库注册的 Issue 类型
+//
+// 存放在目标仓库的 .github/issue-pack/types/<name>.json 中。
+type TypeDefinition struct {
+	Name         string          `json:"name"`
+	Label        string          `json:"label,omitempty"`
+	Description  string          `json:"description,omitempty"`
+	Schema       json.RawMessage `json:"schema,omitempty"`
+	BodyTemplate string          `json:"body_template,omitempty"`
+}
+
+// ParseTypeDefinition 解析类型定义，name 为空时使用 fallbackName
+func ParseTypeDefinition(data []byte, fallbackName string) (*TypeDefinition, error) {
+	var def TypeDefinition
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("解析类型定义失败: %w", err)
+	}
+	if def.Name == "" {
+		def.Name = fallbackName
+	}
+	if def.Name == "" {
+		return nil, fmt.Errorf("类型定义缺少 name")
+	}
+	if len(def.Schema) > 0 {
+		if _, err := ParseJSONSchema(def.Schema); err != nil {
+			return nil, fmt.Errorf("类型 %s: %w", def.Name, err)
+		}
+	}
+	return &def, nil
+}
+
+// ValidatePayload 按类型定义的 schema 校验 payload，未定义 schema 时不校验
+func (d *TypeDefinition) ValidatePayload(payload interface{}) error {
+	if len(d.Schema) == 0 {
+		return nil
+	}
+
+	schema, err := ParseJSONSchema(d.Schema)
+	if err != nil {
+		return err
+	}
+
+	// 统一转换为通用 JSON 结构再校验
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化 payload 失败: %w", err)
+	}
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("解析 payload 失败: %w", err)
+	}
+
+	if errs := schema.Validate(data); len(errs) > 0 {
+		return fmt.Errorf("payload 不符合 %s 类型的 schema:\n  - %s", d.Name, strings.Join(errs, "\n  - "))
+	}
+	return nil
+}
+
+// IsBuiltinType 判断是否为内置类型
+func IsBuiltinType(t IssueType) bool {
+	for _, b := range BuiltinTypes {
+		if b == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// JSONSchema JSON Schema 的子集，用于校验自定义类型的 payload
+//
+// 支持 type、required、properties、additionalProperties、items、enum、
+// minLength、maxLength、pattern、minimum、maximum、minItems、maxItems。
+type JSONSchema struct {
+	Type                 string                 `json:"type,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MaxItems             *int                   `json:"maxItems,omitempty"`
+}
+
+// ParseJSONSchema 解析 JSON Schema
+func ParseJSONSchema(data []byte) (*JSONSchema, error) {
+	var schema JSONSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("解析 schema 失败: %w", err)
+	}
+	return &schema, nil
+}
+
+// Validate 校验数据，返回全部不符合项；数据应为 json.Unmarshal 到 interface{} 的结果
+func (s *JSONSchema) Validate(data interface{}) []string {
+	var errs []string
+	s.validate("payload", data, &errs)
+	return errs
+}
+
+func (s *JSONSchema) validate(path string, data interface{}, errs *[]string) {
+	if s == nil {
+		return
+	}
+
+	if s.Type != "" && !matchesType(s.Type, data) {
+		*errs = append(*errs, fmt.Sprintf("%s: 应为 %s 类型", path, s.Type))
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, data) {
+		*errs = append(*errs, fmt.Sprintf("%s: 取值必须是 %s 之一", path, formatEnum(s.Enum)))
+	}
+
+	switch v := data.(type) {
+	case string:
+		length := len([]rune(v))
+		if s.MinLength != nil && length < *s.MinLength {
+			*errs = append(*errs, fmt.Sprintf("%s: 长度不能少于 %d", path, *s.MinLength))
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			*errs = append(*errs, fmt.Sprintf("%s: 长度不能超过 %d", path, *s.MaxLength))
+		}
+		if s.Pattern != "" {
+			re, err := regexp.Compile(s.Pattern)
+			if err != nil {
+				*errs = append(*errs, fmt.Sprintf("%s: schema 中的 pattern 无效: %v", path, err))
+			} else if !re.MatchString(v) {
+				*errs = append(*errs, fmt.Sprintf("%s: 不匹配格式 %s", path, s.Pattern))
+			}
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			*errs = append(*errs, fmt.Sprintf("%s: 不能小于 %v", path, *s.Minimum))
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			*errs = append(*errs, fmt.Sprintf("%s: 不能大于 %v", path, *s.Maximum))
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			*errs = append(*errs, fmt.Sprintf("%s: 至少需要 %d 项", path, *s.MinItems))
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			*errs = append(*errs, fmt.Sprintf("%s: 最多允许 %d 项", path, *s.MaxItems))
+		}
+		for i, item := range v {
+			s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+		}
+	case map[string]interface{}:
+		for _, field := range s.Required {
+			if _, ok := v[field]; !ok {
+				*errs = append(*errs, fmt.Sprintf("%s.%s: 缺少必需字段", path, field))
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if prop, ok := s.Properties[key]; ok {
+				prop.validate(path+"."+key, v[key], errs)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				*errs = append(*errs, fmt.Sprintf("%s.%s: 不允许的字段", path, key))
+			}
+		}
+	}
+}
+
+// matchesType 判断数据是否符合 JSON Schema 类型
+func matchesType(schemaType string, data interface{}) bool {
+	switch schemaType {
+	case "object":
+		_, ok := data.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := data.([]interface{})
+		return ok
+	case "string":
+		_, ok := data.(string)
+		return ok
+	case "number":
+		_, ok := data.(float64)
+		return ok
+	case "integer":
+		v, ok := data.(float64)
+		return ok && v == math.Trunc(v)
+	case "boolean":
+		_, ok := data.(bool)
+		return ok
+	case "null":
+		return data == nil
+	}
+	return true
+}
+
+func inEnum(enum []interface{}, data interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(data) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprint(e)
+	}
+	return strings.Join(values, "/")
+}
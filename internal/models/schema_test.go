@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const testSchema = `{
+  "type": "object",
+  "required": ["title", "priority"],
+  "additionalProperties": false,
+  "properties": {
+    "title": {"type": "string", "minLength": 3, "maxLength": 10},
+    "priority": {"type": "string", "enum": ["low", "high"]},
+    "version": {"type": "string", "pattern": "^v[0-9]+$"},
+    "count": {"type": "integer", "minimum": 1, "maximum": 5},
+    "tags": {"type": "array", "minItems": 1, "maxItems": 2, "items": {"type": "string"}},
+    "meta": {"type": "object", "required": ["id"]}
+  }
+}`
+
+func TestJSONSchemaValidate(t *testing.T) {
+	schema, err := ParseJSONSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		payload string
+		want    []string
+	}{
+		{
+			name:    "合法",
+			payload: `{"title": "登录失败", "priority": "high", "version": "v2", "count": 3, "tags": ["ui"], "meta": {"id": 1}}`,
+		},
+		{
+			name:    "根类型错误",
+			payload: `["x"]`,
+			want:    []string{"payload: 应为 object 类型"},
+		},
+		{
+			name:    "缺少必需字段",
+			payload: `{"title": "abc"}`,
+			want:    []string{"payload.priority: 缺少必需字段"},
+		},
+		{
+			name:    "不允许的字段",
+			payload: `{"title": "abc", "priority": "low", "extra": true}`,
+			want:    []string{"payload.extra: 不允许的字段"},
+		},
+		{
+			name:    "字符串约束",
+			payload: `{"title": "ab", "priority": "urgent", "version": "2.0"}`,
+			want: []string{
+				"payload.priority: 取值必须是 low/high 之一",
+				"payload.title: 长度不能少于 3",
+				"payload.version: 不匹配格式 ^v[0-9]+$",
+			},
+		},
+		{
+			name:    "长度按字符计算",
+			payload: `{"title": "一二三四五六七八九十", "priority": "low"}`,
+		},
+		{
+			name:    "数值约束",
+			payload: `{"title": "abc", "priority": "low", "count": 1.5}`,
+			want:    []string{"payload.count: 应为 integer 类型"},
+		},
+		{
+			name:    "数值范围",
+			payload: `{"title": "abc", "priority": "low", "count": 9}`,
+			want:    []string{"payload.count: 不能大于 5"},
+		},
+		{
+			name:    "数组约束",
+			payload: `{"title": "abc", "priority": "low", "tags": ["a", 1, "c"]}`,
+			want: []string{
+				"payload.tags: 最多允许 2 项",
+				"payload.tags[1]: 应为 string 类型",
+			},
+		},
+		{
+			name:    "嵌套对象",
+			payload: `{"title": "abc", "priority": "low", "meta": {}}`,
+			want:    []string{"payload.meta.id: 缺少必需字段"},
+		},
+	}
+	for _, tt := range tests {
+		var data interface{}
+		if err := json.Unmarshal([]byte(tt.payload), &data); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := schema.Validate(data)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Validate = %q, 期望 %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestJSONSchemaValidateInvalidPattern(t *testing.T) {
+	schema := &JSONSchema{Type: "string", Pattern: "("}
+	if errs := schema.Validate("x"); len(errs) != 1 {
+		t.Errorf("pattern 无效时应报告一项错误, 得到 %q", errs)
+	}
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+	"github.com/shichao402/github-issue-pack/internal/models"
+)
+
+// AmendOptions 修改 Issue 包的选项
+type AmendOptions struct {
+	Repo              string
+	Number            int
+	Payload           interface{}         // 新的 payload，nil 时保持不变
+	Attachments       []models.Attachment // 新增或覆盖的同名附件
+	RemoveAttachments []string            // 删除的附件名
+	Comment           string              // 附在变更评论中的说明
+	DryRun            bool
+}
+
+// AmendResult 修改结果
+type AmendResult struct {
+	Number           int
+	GistURL          string
+	Revision         string // 新版本，DryRun 时为空
+	PreviousRevision string
+	ChangedFields    []string // 变化的 payload 顶层字段
+	AddedFiles       []string // 新增或更新的附件
+	RemovedFiles     []string // 删除的附件
+	Package          *models.IssuePackage
+}
+
+// Amend 修改 Issue 关联的 Issue 包
+//
+// 更新 Gist 生成新版本（旧版本保留在 Gist 历史中），payload 变化时按新 payload
+// 重新生成 Issue 描述中的摘要，并在 Issue 下发表评论说明变更。
+func (s *IssueService) Amend(opts AmendOptions) (*AmendResult, error) {
+	owner, repo, err := parseRepo(opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	issue, err := s.client.GetIssue(owner, repo, opts.Number)
+	if err != nil {
+		return nil, err
+	}
+	gistID := extractGistID(extractGistURL(issue.Body))
+	if gistID == "" {
+		return nil, fmt.Errorf("Issue #%d 没有关联的 Issue 包", opts.Number)
+	}
+	gist, err := s.client.GetGist(gistID)
+	if err != nil {
+		return nil, err
+	}
+	file, ok := gist.Files[PayloadFileName]
+	if !ok {
+		return nil, fmt.Errorf("Gist %s 中没有 %s", gistID, PayloadFileName)
+	}
+	pkg, err := models.ParseIssuePackage(file.Content)
+	if err != nil {
+		return nil, fmt.Errorf("解析 Issue 包失败: %w", err)
+	}
+
+	result := &AmendResult{Number: opts.Number, GistURL: gist.HTMLURL}
+	if len(gist.History) > 0 {
+		result.PreviousRevision = gist.History[0].Version
+	}
+
+	// payload 按目标仓库注册的 schema 和 manifest 重新校验
+	var typeDef *models.TypeDefinition
+	if opts.Payload != nil {
+		typeDef, _, err = s.ResolveType(opts.Repo, pkg.Type)
+		if err != nil {
+			return nil, err
+		}
+		if typeDef != nil {
+			if err := typeDef.ValidatePayload(opts.Payload); err != nil {
+				return nil, err
+			}
+		}
+		manifest, err := s.FetchManifest(opts.Repo)
+		if err != nil {
+			return nil, err
+		}
+		if manifest != nil {
+			if err := manifest.Check(pkg.Type, opts.Payload); err != nil {
+				return nil, err
+			}
+		}
+
+		payload, err := json.Marshal(opts.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("序列化 payload 失败: %w", err)
+		}
+		result.ChangedFields = changedFields(pkg.Payload, payload)
+		pkg.Payload = payload
+	}
+
+	// 附件：同名覆盖，其余保留
+	files := map[string]string{}
+	for _, att := range opts.Attachments {
+		if existing, ok := findAttachment(pkg.Attachments, att.Name); ok && existing.Content == att.Content {
+			continue
+		}
+		pkg.Attachments = setAttachment(pkg.Attachments, att)
+		files[att.Name] = att.Content
+		result.AddedFiles = append(result.AddedFiles, att.Name)
+	}
+	var remove []string
+	for _, name := range opts.RemoveAttachments {
+		if _, ok := findAttachment(pkg.Attachments, name); !ok {
+			return nil, fmt.Errorf("Issue 包中没有附件 %s", name)
+		}
+		pkg.Attachments = removeAttachment(pkg.Attachments, name)
+		if _, ok := gist.Files[name]; ok && name != PayloadFileName {
+			remove = append(remove, name)
+		}
+		result.RemovedFiles = append(result.RemovedFiles, name)
+	}
+
+	if len(result.ChangedFields) == 0 && len(result.AddedFiles) == 0 && len(result.RemovedFiles) == 0 {
+		return nil, fmt.Errorf("Issue 包内容没有变化")
+	}
+
+	pkg.Meta.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	pkg.Meta.GitHubIssueVersion = models.ToolVersion
+	result.Package = pkg
+	if opts.DryRun {
+		return result, nil
+	}
+
+	pkgJSON, err := pkg.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("序列化 Issue 包失败: %w", err)
+	}
+	files[PayloadFileName] = pkgJSON
+	updated, err := s.client.UpdateGist(gistID, files, remove)
+	if err != nil {
+		return nil, err
+	}
+	if len(updated.History) > 0 {
+		result.Revision = updated.History[0].Version
+	}
+
+	if len(result.ChangedFields) > 0 {
+		summary, err := renderBodyTemplate(typeDef, issue.Title, pkg.Payload)
+		if err != nil {
+			return result, fmt.Errorf("Issue 包已更新，但生成 Issue 描述失败: %w", err)
+		}
+		if body := amendedBody(issue.Body, pkg.Type, issue.Title, summary, gist.HTMLURL); body != issue.Body {
+			if err := s.client.UpdateIssueBody(owner, repo, opts.Number, body); err != nil {
+				return result, fmt.Errorf("Issue 包已更新，但%w", err)
+			}
+		}
+	}
+
+	if err := s.client.AddComment(owner, repo, opts.Number, amendComment(result, opts.Comment)); err != nil {
+		return result, fmt.Errorf("Issue 包已更新，但添加评论失败: %w", err)
+	}
+	return result, nil
+}
+
+// amendedBody 按新的摘要重新生成 Issue 描述
+//
+// 保留 buildIssueBody 生成部分之前的内容（如 import 添加的原 Issue 链接）。
+func amendedBody(body string, issueType models.IssueType, title, summary, gistURL string) string {
+	prefix := ""
+	if i := strings.Index(body, fmt.Sprintf("## %s: ", issueType)); i > 0 {
+		prefix = body[:i]
+	}
+	return prefix + buildIssueBody(issueType, title, summary, gistURL)
+}
+
+// amendComment 生成说明 Issue 包变更的评论
+func amendComment(r *AmendResult, note string) string {
+	var b strings.Builder
+	b.WriteString("📦 **Issue 包已更新**")
+	if r.Revision != "" {
+		fmt.Fprintf(&b, "（版本 `%s`", shortSHA(r.Revision))
+		if r.PreviousRevision != "" {
+			fmt.Fprintf(&b, "，上一版本 `%s`", shortSHA(r.PreviousRevision))
+		}
+		b.WriteString("）")
+	}
+	b.WriteString("\n\n")
+	if len(r.ChangedFields) > 0 {
+		fmt.Fprintf(&b, "- 变更字段: %s\n", codeList(r.ChangedFields))
+	}
+	if len(r.AddedFiles) > 0 {
+		fmt.Fprintf(&b, "- 新增/更新附件: %s\n", codeList(r.AddedFiles))
+	}
+	if len(r.RemovedFiles) > 0 {
+		fmt.Fprintf(&b, "- 删除附件: %s\n", codeList(r.RemovedFiles))
+	}
+	if note = strings.TrimSpace(note); note != "" {
+		fmt.Fprintf(&b, "\n%s\n", note)
+	}
+	fmt.Fprintf(&b, "\n<sub>查看差异: `github-issue get %d --diff`</sub>", r.Number)
+	return b.String()
+}
+
+func codeList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = "`" + item + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// shortSHA 返回版本 SHA 的前 7 位
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// changedFields 比较新旧 payload，返回变化的顶层字段（按字段名排序）
+func changedFields(before, after json.RawMessage) []string {
+	var old, cur map[string]interface{}
+	if json.Unmarshal(before, &old) != nil || json.Unmarshal(after, &cur) != nil {
+		if string(before) != string(after) {
+			return []string{"payload"}
+		}
+		return nil
+	}
+
+	var fields []string
+	for key, value := range cur {
+		if prev, ok := old[key]; !ok || !reflect.DeepEqual(prev, value) {
+			fields = append(fields, key)
+		}
+	}
+	for key := range old {
+		if _, ok := cur[key]; !ok {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func findAttachment(attachments []models.Attachment, name string) (models.Attachment, bool) {
+	for _, att := range attachments {
+		if att.Name == name {
+			return att, true
+		}
+	}
+	return models.Attachment{}, false
+}
+
+func setAttachment(attachments []models.Attachment, att models.Attachment) []models.Attachment {
+	for i := range attachments {
+		if attachments[i].Name == att.Name {
+			attachments[i] = att
+			return attachments
+		}
+	}
+	return append(attachments, att)
+}
+
+func removeAttachment(attachments []models.Attachment, name string) []models.Attachment {
+	var result []models.Attachment
+	for _, att := range attachments {
+		if att.Name != name {
+			result = append(result, att)
+		}
+	}
+	return result
+}
+
+// GetRevision 获取 Issue 及其 Issue 包的指定版本
+//
+// rev 为空时返回最新版本；可以是版本 SHA（至少 4 位前缀）或序号（1 为最初版本）。
+func (s *IssueService) GetRevision(repoStr string, number int, rev string) (*GetResult, error) {
+	result, err := s.Get(repoStr, number)
+	if err != nil {
+		return nil, err
+	}
+	if rev == "" {
+		return result, nil
+	}
+	if len(result.Revisions) == 0 {
+		return nil, fmt.Errorf("Issue #%d 没有可用的 Issue 包版本历史", number)
+	}
+
+	sha, err := resolveRevision(result.Revisions, rev)
+	if err != nil {
+		return nil, err
+	}
+	if sha == result.Revision {
+		return result, nil
+	}
+
+	gist, err := s.client.GetGistRevision(extractGistID(extractGistURL(result.Issue.Body)), sha)
+	if err != nil {
+		return nil, err
+	}
+	file, ok := gist.Files[PayloadFileName]
+	if !ok {
+		return nil, fmt.Errorf("版本 %s 中没有 %s", shortSHA(sha), PayloadFileName)
+	}
+	pkg, err := models.ParseIssuePackage(file.Content)
+	if err != nil {
+		return nil, fmt.Errorf("解析版本 %s 的 Issue 包失败: %w", shortSHA(sha), err)
+	}
+	result.Package = pkg
+	result.Revision = sha
+	return result, nil
+}
+
+// PreviousRevision 返回 rev 的上一个版本，rev 为空时为最新版本的上一个版本
+func PreviousRevision(history []github.GistRevision, rev string) (string, error) {
+	sha := ""
+	if len(history) > 0 {
+		sha = history[0].Version
+	}
+	if rev != "" {
+		var err error
+		if sha, err = resolveRevision(history, rev); err != nil {
+			return "", err
+		}
+	}
+	for i, r := range history {
+		if r.Version == sha && i+1 < len(history) {
+			return history[i+1].Version, nil
+		}
+	}
+	return "", fmt.Errorf("版本 %s 是最初版本，没有上一个版本", shortSHA(sha))
+}
+
+// resolveRevision 将序号或 SHA 前缀解析为完整的版本 SHA
+func resolveRevision(history []github.GistRevision, rev string) (string, error) {
+	if n, err := strconv.Atoi(rev); err == nil && len(rev) < 4 {
+		if n < 1 || n > len(history) {
+			return "", fmt.Errorf("版本序号超出范围: %d (共 %d 个版本)", n, len(history))
+		}
+		return history[len(history)-n].Version, nil
+	}
+	if len(rev) < 4 {
+		return "", fmt.Errorf("版本 SHA 至少需要 4 位: %s", rev)
+	}
+
+	var matched []string
+	for _, r := range history {
+		if strings.HasPrefix(r.Version, rev) {
+			matched = append(matched, r.Version)
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return "", fmt.Errorf("找不到版本: %s", rev)
+	case 1:
+		return matched[0], nil
+	}
+	return "", fmt.Errorf("版本 %s 不唯一，请提供更长的 SHA", rev)
+}
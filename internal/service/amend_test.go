@@ -0,0 +1,73 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+)
+
+func TestResolveRevision(t *testing.T) {
+	// GitHub 按时间倒序返回版本历史，序号 1 为最初版本
+	history := []github.GistRevision{
+		{Version: "c3d4e5f60000000000000000000000000000000a"},
+		{Version: "abcd1111000000000000000000000000000000bb"},
+		{Version: "abcd2222000000000000000000000000000000cc"},
+	}
+
+	tests := []struct {
+		rev     string
+		want    string
+		wantErr string
+	}{
+		{rev: "1", want: history[2].Version},
+		{rev: "3", want: history[0].Version},
+		{rev: "c3d4", want: history[0].Version},
+		{rev: "abcd1", want: history[1].Version},
+		{rev: history[2].Version, want: history[2].Version},
+		{rev: "0", wantErr: "超出范围"},
+		{rev: "4", wantErr: "超出范围"},
+		{rev: "abc", wantErr: "至少需要 4 位"},
+		{rev: "abcd", wantErr: "不唯一"},
+		{rev: "ffff", wantErr: "找不到版本"},
+	}
+	for _, tt := range tests {
+		got, err := resolveRevision(history, tt.rev)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("resolveRevision(%q) = %q, %v, 期望错误包含 %q", tt.rev, got, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("resolveRevision(%q) 报错: %v", tt.rev, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resolveRevision(%q) = %q, 期望 %q", tt.rev, got, tt.want)
+		}
+	}
+}
+
+func TestChangedFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          []string
+	}{
+		{"无变化", `{"a": 1, "b": [1, 2]}`, `{"b": [1, 2], "a": 1}`, nil},
+		{"修改", `{"a": 1, "b": "x"}`, `{"a": 2, "b": "x"}`, []string{"a"}},
+		{"新增和删除", `{"a": 1, "c": true}`, `{"a": 1, "b": null}`, []string{"b", "c"}},
+		{"嵌套字段只报告顶层", `{"env": {"os": "linux"}}`, `{"env": {"os": "macos"}}`, []string{"env"}},
+		{"非对象 payload", `[1]`, `[2]`, []string{"payload"}},
+		{"非对象 payload 无变化", `"x"`, `"x"`, nil},
+	}
+	for _, tt := range tests {
+		got := changedFields(json.RawMessage(tt.before), json.RawMessage(tt.after))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: changedFields = %q, 期望 %q", tt.name, got, tt.want)
+		}
+	}
+}
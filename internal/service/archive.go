@@ -0,0 +1,227 @@
+package service
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/models"
+)
+
+// ArchiveFormat 导出归档的格式版本，写入 manifest.json
+const ArchiveFormat = "github-issue-archive-v1"
+
+// 归档中的文件
+const (
+	archiveManifestFile = "manifest.json"
+	archiveIssuesFile   = "issues.jsonl"
+	archivePackagesDir  = "packages"
+)
+
+// ArchiveManifest 归档描述（manifest.json）
+type ArchiveManifest struct {
+	Format      string      `json:"format"`
+	Repo        string      `json:"repo"`
+	ExportedAt  string      `json:"exported_at"`
+	ToolVersion string      `json:"tool_version"`
+	Issues      int         `json:"issues"`
+	Labels      []LabelSpec `json:"labels"` // 导出的 Issue 使用的标签
+}
+
+// ArchivedIssue issues.jsonl 中的一行
+//
+// Issue 包解包到 packages/<number>/：Package 指向其中的 issue-payload.json，
+// 附件另存到 packages/<number>/attachments/ 便于直接查看，导入时以 issue-payload.json 为准。
+type ArchivedIssue struct {
+	Number    int             `json:"number"`
+	Title     string          `json:"title"`
+	State     string          `json:"state"`
+	Type      string          `json:"type"`
+	Status    string          `json:"status"`
+	Labels    []string        `json:"labels"`
+	Author    string          `json:"author"`
+	CreatedAt string          `json:"created_at"`
+	ClosedAt  string          `json:"closed_at,omitempty"`
+	URL       string          `json:"url"`
+	Body      string          `json:"body"`
+	GistURL   string          `json:"gist_url,omitempty"`
+	Package   string          `json:"package,omitempty"`
+	Comments  []ThreadComment `json:"comments"`
+}
+
+// isTarArchive 按扩展名判断归档是否为 tar.gz，否则为目录
+func isTarArchive(p string) bool {
+	return strings.HasSuffix(p, ".tar.gz") || strings.HasSuffix(p, ".tgz")
+}
+
+// archiveWriter 写入归档文件，name 为归档内以 / 分隔的相对路径
+type archiveWriter interface {
+	WriteFile(name string, data []byte) error
+	Close() error
+}
+
+// createArchive 创建归档：.tar.gz/.tgz 写入压缩包，否则写入目录（须不存在或为空）
+func createArchive(p string) (archiveWriter, error) {
+	if isTarArchive(p) {
+		f, err := os.Create(p)
+		if err != nil {
+			return nil, fmt.Errorf("创建归档失败: %w", err)
+		}
+		gz := gzip.NewWriter(f)
+		return &tarArchiveWriter{file: f, gz: gz, tw: tar.NewWriter(gz), modTime: time.Now()}, nil
+	}
+
+	if entries, err := os.ReadDir(p); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("归档目录不为空: %s", p)
+	}
+	if err := os.MkdirAll(p, 0755); err != nil {
+		return nil, fmt.Errorf("创建归档目录失败: %w", err)
+	}
+	return dirArchiveWriter(p), nil
+}
+
+type dirArchiveWriter string
+
+func (d dirArchiveWriter) WriteFile(name string, data []byte) error {
+	p := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := os.WriteFile(p, data, 0644); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+func (d dirArchiveWriter) Close() error { return nil }
+
+type tarArchiveWriter struct {
+	file    *os.File
+	gz      *gzip.Writer
+	tw      *tar.Writer
+	modTime time.Time
+}
+
+func (t *tarArchiveWriter) WriteFile(name string, data []byte) error {
+	hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: t.modTime}
+	if err := t.tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	if _, err := t.tw.Write(data); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+func (t *tarArchiveWriter) Close() error {
+	err := t.tw.Close()
+	if e := t.gz.Close(); err == nil {
+		err = e
+	}
+	if e := t.file.Close(); err == nil {
+		err = e
+	}
+	return err
+}
+
+// readArchive 读取归档中的全部文件，返回 相对路径 → 内容
+func readArchive(p string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	if !isTarArchive(p) {
+		err := filepath.WalkDir(p, func(fp string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(p, fp)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(fp)
+			if err != nil {
+				return err
+			}
+			files[filepath.ToSlash(rel)] = data
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("读取归档失败: %w", err)
+		}
+		return files, nil
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, fmt.Errorf("读取归档失败: %w", err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("读取归档失败: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取归档失败: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("读取归档失败 %s: %w", hdr.Name, err)
+		}
+		files[path.Clean(hdr.Name)] = data
+	}
+	return files, nil
+}
+
+// archivePackagePath Issue 包在归档中的路径
+func archivePackagePath(number int) string {
+	return fmt.Sprintf("%s/%d/%s", archivePackagesDir, number, PayloadFileName)
+}
+
+// archiveAttachmentPaths 附件在归档中的路径，与 attachments 一一对应
+//
+// 附件名经 archiveFileName 清理后写入 packages/<number>/attachments/，
+// 清理后重名（不区分大小写）的附件在扩展名前追加 -2、-3 等序号，不互相覆盖。
+func archiveAttachmentPaths(number int, attachments []models.Attachment) []string {
+	paths := make([]string, len(attachments))
+	seen := map[string]bool{}
+	for i, att := range attachments {
+		name := archiveFileName(att.Name)
+		ext := path.Ext(name)
+		base := strings.TrimSuffix(name, ext)
+		for n := 2; seen[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s-%d%s", base, n, ext)
+		}
+		seen[strings.ToLower(name)] = true
+		paths[i] = fmt.Sprintf("%s/%d/attachments/%s", archivePackagesDir, number, name)
+	}
+	return paths
+}
+
+// archiveFileName 将附件名清理为单个安全的文件名：路径分隔符和控制字符替换为 "_"，
+// 空名称及 "."、".." 等只由点组成的名称改为 attachment
+func archiveFileName(name string) string {
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < 0x20 || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, name))
+	if strings.Trim(name, ".") == "" {
+		return "attachment"
+	}
+	return name
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shichao402/github-issue-pack/internal/models"
+)
+
+func TestArchiveAttachmentPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{
+			name:  "普通文件名",
+			names: []string{"logs.txt", "screen.png"},
+			want:  []string{"logs.txt", "screen.png"},
+		},
+		{
+			name:  "路径分隔符不再截断为同名文件",
+			names: []string{"a/config.yaml", "b/config.yaml", `c\config.yaml`},
+			want:  []string{"a_config.yaml", "b_config.yaml", "c_config.yaml"},
+		},
+		{
+			name:  "只由点组成或为空",
+			names: []string{"..", ".", "", " "},
+			want:  []string{"attachment", "attachment-2", "attachment-3", "attachment-4"},
+		},
+		{
+			name:  "控制字符",
+			names: []string{"a\nb.txt"},
+			want:  []string{"a_b.txt"},
+		},
+		{
+			name:  "清理后重名时追加序号，不区分大小写",
+			names: []string{"log.txt", "LOG.txt", "log.txt", "log-2.txt"},
+			want:  []string{"log.txt", "LOG-2.txt", "log-3.txt", "log-2-2.txt"},
+		},
+	}
+	for _, tt := range tests {
+		attachments := make([]models.Attachment, len(tt.names))
+		for i, name := range tt.names {
+			attachments[i] = models.Attachment{Name: name}
+		}
+		want := make([]string, len(tt.want))
+		for i, name := range tt.want {
+			want[i] = "packages/7/attachments/" + name
+		}
+		if got := archiveAttachmentPaths(7, attachments); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: archiveAttachmentPaths = %q, 期望 %q", tt.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BulkQuery 批量操作的筛选条件
+//
+// 格式为空格分隔的 key:value，例如 "status:processing type:pack-sync older:7d"：
+//   - status: 状态，与 list --status 相同，默认只匹配打开的 Issue
+//   - type:   Issue 类型
+//   - older:  超过该时长未更新（如 7d、2w、36h）
+//
+// 其余词原样作为 GitHub 搜索关键字和限定符（如 author:octocat、"in:title 崩溃"）。
+type BulkQuery struct {
+	Status    string
+	Type      string
+	OlderThan time.Duration
+	Terms     []string
+}
+
+// ParseBulkQuery 解析批量操作的筛选条件
+func ParseBulkQuery(query string) (BulkQuery, error) {
+	var q BulkQuery
+	for _, field := range strings.Fields(query) {
+		key, value, ok := strings.Cut(field, ":")
+		if !ok || value == "" {
+			q.Terms = append(q.Terms, field)
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "status":
+			if value != "all" && !isStatus(value) {
+				return q, fmt.Errorf("无效的状态: %s", value)
+			}
+			q.Status = value
+		case "type":
+			q.Type = value
+		case "older":
+			d, err := ParseDuration(value)
+			if err != nil {
+				return q, err
+			}
+			q.OlderThan = d
+		default:
+			q.Terms = append(q.Terms, field)
+		}
+	}
+	if q.Status == "" && q.Type == "" && q.OlderThan == 0 && len(q.Terms) == 0 {
+		return q, fmt.Errorf("筛选条件不能为空")
+	}
+	return q, nil
+}
+
+func isStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectIssues 按筛选条件查找标准化 Issue，结果按 GitHub 搜索的默认顺序返回
+//
+// GitHub 搜索最多返回 1000 条结果，匹配更多时返回错误，避免批量操作只处理其中一部分。
+func (s *IssueService) SelectIssues(repo string, q BulkQuery) ([]IssueInfo, error) {
+	terms := append([]string{}, q.Terms...)
+	if q.OlderThan > 0 {
+		terms = append(terms, "updated:<"+time.Now().Add(-q.OlderThan).UTC().Format("2006-01-02T15:04:05Z"))
+	}
+	issues, total, err := s.search(SearchOptions{
+		ListOptions: ListOptions{Repo: repo, Status: q.Status, Type: q.Type},
+		Query:       strings.Join(terms, " "),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if total > len(issues) {
+		return nil, fmt.Errorf("筛选条件匹配 %d 个 Issue，超过 GitHub 搜索最多返回的 %d 个，请缩小条件分批处理", total, len(issues))
+	}
+	return issues, nil
+}
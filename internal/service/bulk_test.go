@@ -0,0 +1,84 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+)
+
+func TestParseBulkQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    BulkQuery
+		wantErr bool
+	}{
+		{
+			query: "status:processing type:pack-sync older:7d",
+			want:  BulkQuery{Status: "processing", Type: "pack-sync", OlderThan: 7 * 24 * time.Hour},
+		},
+		{
+			query: "STATUS:all author:octocat in:title 崩溃",
+			want:  BulkQuery{Status: "all", Terms: []string{"author:octocat", "in:title", "崩溃"}},
+		},
+		{
+			query: "older:36h label: foo",
+			want:  BulkQuery{OlderThan: 36 * time.Hour, Terms: []string{"label:", "foo"}},
+		},
+		{query: "status:done", wantErr: true},
+		{query: "older:soon", wantErr: true},
+		{query: "   ", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBulkQuery(tt.query)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseBulkQuery(%q) = %+v, 期望报错", tt.query, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseBulkQuery(%q) 报错: %v", tt.query, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseBulkQuery(%q) = %+v, 期望 %+v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSelectIssuesSearchCap(t *testing.T) {
+	labels := []github.Label{{Name: LabelCursorToolset}, {Name: "pack-sync"}, {Name: "pending"}}
+	var total int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/issues" {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total_count": total,
+			"items": []github.Issue{
+				{Number: 1, Title: "a", State: "open", CreatedAt: "2024-01-01T00:00:00Z", Labels: labels},
+			},
+		})
+	}))
+	defer srv.Close()
+	svc := NewIssueServiceWithConfig(Config{Token: "token", APIHost: srv.URL})
+
+	total = 1
+	issues, err := svc.SelectIssues("o/r", BulkQuery{Type: "pack-sync"})
+	if err != nil || len(issues) != 1 {
+		t.Fatalf("SelectIssues = %v, %v, 期望 1 个 Issue", issues, err)
+	}
+
+	// 匹配数超过实际返回数时，批量操作只能处理一部分，应报错
+	total = 1500
+	if _, err := svc.SelectIssues("o/r", BulkQuery{Type: "pack-sync"}); err == nil || !strings.Contains(err.Error(), "1500") {
+		t.Errorf("匹配超过返回上限时错误 = %v, 期望说明匹配数", err)
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+)
+
+// statusMarkerPattern 工具发表的状态评论以 HTML 注释标记开头，GitHub 渲染时不可见
+var statusMarkerPattern = regexp.MustCompile(`^<!-- github-issue:status=([A-Za-z0-9_-]+) -->\r?\n?`)
+
+// reminderMarker 标记 stale 发表的催促评论
+const reminderMarker = "<!-- github-issue:reminder -->"
+
+// statusComment 为状态变更附带的评论加上标记，便于与人工评论区分
+func statusComment(status, body string) string {
+	return fmt.Sprintf("<!-- github-issue:status=%s -->\n%s", status, body)
+}
+
+// needsInfoComment 将 needs-info 的问题格式化为结构化评论
+func needsInfoComment(question string) string {
+	return statusComment("needs-info", fmt.Sprintf(
+		"### ❓ 需要更多信息\n\n%s\n\n---\n<sub>请直接回复此 Issue 或更新 Issue 包，Issue 将自动恢复为待处理。</sub>", question))
+}
+
+// ThreadComment 会话中的一条评论
+type ThreadComment struct {
+	ID                int64  `json:"id"`
+	Author            string `json:"author"`
+	AuthorAssociation string `json:"author_association,omitempty"`
+	Body              string `json:"body"` // 已去除状态标记
+	CreatedAt         string `json:"created_at"`
+	URL               string `json:"url,omitempty"`
+	Status            string `json:"status,omitempty"`   // 工具生成的状态评论设置的状态，人工评论为空
+	Reminder          bool   `json:"reminder,omitempty"` // stale 发表的催促评论
+}
+
+// IsStatus 是否为工具生成的状态评论
+func (c ThreadComment) IsStatus() bool {
+	return c.Status != ""
+}
+
+// IsGenerated 是否为工具生成的评论（状态评论或催促评论）
+func (c ThreadComment) IsGenerated() bool {
+	return c.Status != "" || c.Reminder
+}
+
+func toThreadComment(c github.Comment) ThreadComment {
+	tc := ThreadComment{
+		ID:                c.ID,
+		Author:            c.User.Login,
+		AuthorAssociation: c.AuthorAssociation,
+		Body:              c.Body,
+		CreatedAt:         c.CreatedAt,
+		URL:               c.HTMLURL,
+	}
+	if m := statusMarkerPattern.FindStringSubmatch(c.Body); m != nil {
+		tc.Status = m[1]
+		tc.Body = strings.TrimPrefix(c.Body, m[0])
+	} else if strings.HasPrefix(c.Body, reminderMarker) {
+		tc.Reminder = true
+		tc.Body = strings.TrimLeft(strings.TrimPrefix(c.Body, reminderMarker), "\r\n")
+	}
+	return tc
+}
+
+// Comments 获取 Issue 的全部评论，按创建时间升序
+func (s *IssueService) Comments(repoStr string, number int) ([]ThreadComment, error) {
+	owner, repo, err := parseRepo(repoStr)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.client.ListComments(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	thread := make([]ThreadComment, 0, len(comments))
+	for _, c := range comments {
+		thread = append(thread, toThreadComment(c))
+	}
+	return thread, nil
+}
+
+// Reply 在 Issue 下发表评论，不改变状态
+func (s *IssueService) Reply(repoStr string, number int, body string) error {
+	owner, repo, err := parseRepo(repoStr)
+	if err != nil {
+		return err
+	}
+	if body == "" {
+		return fmt.Errorf("回复内容不能为空")
+	}
+	return s.client.AddComment(owner, repo, number, body)
+}
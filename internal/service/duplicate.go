@@ -0,0 +1,387 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+	"github.com/shichao402/github-issue-pack/internal/models"
+)
+
+// DefaultDuplicateThreshold 判定为可能重复的默认相似度
+const DefaultDuplicateThreshold = 0.6
+
+// maxDuplicateCandidates 检查重复时最多比较的打开 Issue 数（按创建时间倒序）
+const maxDuplicateCandidates = 100
+
+// DuplicateCandidate 可能重复的 Issue
+type DuplicateCandidate struct {
+	Number int     `json:"number"`
+	Title  string  `json:"title"`
+	URL    string  `json:"url"`
+	Status string  `json:"status"`
+	Score  float64 `json:"score,omitempty"`  // 相似度 0~1
+	Reason string  `json:"reason,omitempty"` // 判定依据
+	Closed bool    `json:"closed,omitempty"` // dedupe 已将其以 duplicate 关闭
+	Error  string  `json:"error,omitempty"`
+}
+
+// dupDoc 参与重复比较的 Issue 内容
+type dupDoc struct {
+	title  string
+	fields map[string]interface{} // payload 顶层字段
+}
+
+func newDupDoc(title string, payload interface{}) dupDoc {
+	doc := dupDoc{title: title, fields: map[string]interface{}{}}
+	var data []byte
+	switch p := payload.(type) {
+	case json.RawMessage:
+		data = p
+	default:
+		data, _ = json.Marshal(p)
+	}
+	json.Unmarshal(data, &doc.fields)
+	if t, ok := doc.fields["title"].(string); ok && t != "" {
+		doc.title = t
+	}
+	return doc
+}
+
+func (d dupDoc) field(name string) string {
+	s, _ := d.fields[name].(string)
+	return strings.TrimSpace(s)
+}
+
+// similarity 计算两个同类型 Issue 的相似度
+//
+// 包类型按关键字段精确匹配：pack-register 比较 repository（其次 name），
+// pack-sync 比较 repository + version；其余类型按标题和描述的词语重合度模糊匹配。
+func similarity(issueType models.IssueType, a, b dupDoc) (float64, string) {
+	switch issueType {
+	case models.TypePackRegister:
+		if repo := normalizeRepoURL(a.field("repository")); repo != "" && repo == normalizeRepoURL(b.field("repository")) {
+			return 1, "repository 相同"
+		}
+		if name := strings.ToLower(a.field("name")); name != "" && name == strings.ToLower(b.field("name")) {
+			return 0.8, "包名相同"
+		}
+		return 0, ""
+	case models.TypePackSync:
+		repo := normalizeRepoURL(a.field("repository"))
+		if repo != "" && repo == normalizeRepoURL(b.field("repository")) &&
+			strings.TrimPrefix(a.field("version"), "v") == strings.TrimPrefix(b.field("version"), "v") {
+			return 1, "repository 和 version 相同"
+		}
+		return 0, ""
+	}
+
+	title := diceCoefficient(textTokens(a.title), textTokens(b.title))
+	descA, descB := a.field("description"), b.field("description")
+	if descA == "" || descB == "" {
+		return title, fmt.Sprintf("标题相似度 %.2f", title)
+	}
+	desc := diceCoefficient(textTokens(descA), textTokens(descB))
+	return 0.6*title + 0.4*desc, fmt.Sprintf("标题相似度 %.2f，描述相似度 %.2f", title, desc)
+}
+
+// normalizeRepoURL 统一仓库地址的写法：去掉协议、github.com 前缀、.git 后缀，转为小写
+func normalizeRepoURL(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, prefix := range []string{"https://", "http://", "git@", "github.com/", "github.com:"} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+	return s
+}
+
+// textTokens 将文本切分为词：英文和数字按单词（小写），中日韩文字按相邻两字
+func textTokens(s string) map[string]bool {
+	tokens := map[string]bool{}
+	var word []rune
+	var prevCJK rune
+	flush := func() {
+		if len(word) > 1 {
+			tokens[string(word)] = true
+		}
+		word = word[:0]
+	}
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			flush()
+			if prevCJK != 0 {
+				tokens[string([]rune{prevCJK, r})] = true
+			} else {
+				tokens[string(r)] = true
+			}
+			prevCJK = r
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = append(word, r)
+		default:
+			flush()
+		}
+		prevCJK = 0
+	}
+	flush()
+	return tokens
+}
+
+// diceCoefficient 两个词集合的 Dice 系数 2|A∩B|/(|A|+|B|)
+func diceCoefficient(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for t := range a {
+		if b[t] {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+// DuplicateQuery 检查重复的内容
+type DuplicateQuery struct {
+	Repo      string
+	Type      models.IssueType
+	Title     string
+	Payload   interface{}
+	Exclude   int     // 不参与比较的 Issue 编号（如 Issue 自身）
+	Threshold float64 // 为 0 时使用 DefaultDuplicateThreshold
+}
+
+// FindDuplicates 在同类型的打开 Issue 中查找与给定内容相似的 Issue，按相似度降序返回
+//
+// 为减少 API 调用，只为可能重复的候选读取 Issue 包：模糊匹配的类型先按标题筛选，
+// 标题相似度低于 titlePreThreshold 的 Issue 不可能达到阈值；包类型用仓库名和包名
+// 搜索候选。读取候选 Issue 包失败时仍按已有内容比较，并在 warnings 中说明。
+func (s *IssueService) FindDuplicates(q DuplicateQuery) (result []DuplicateCandidate, warnings []string, err error) {
+	owner, repo, err := parseRepo(q.Repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	threshold := q.Threshold
+	if threshold <= 0 {
+		threshold = DefaultDuplicateThreshold
+	}
+
+	doc := newDupDoc(q.Title, q.Payload)
+	f := s.listFilter(ListOptions{Repo: q.Repo, Type: string(q.Type)})
+	var issues []github.Issue
+	if isPackType(q.Type) {
+		terms := packSearchTerms(doc)
+		if len(terms) == 0 {
+			return []DuplicateCandidate{}, nil, nil
+		}
+		query := fmt.Sprintf("%s (%s) in:title,body", searchQualifiers(q.Repo, f), strings.Join(terms, " OR "))
+		issues, err = s.client.SearchIssues(query, maxDuplicateCandidates)
+	} else {
+		issues, err = s.client.ListIssues(owner, repo, f.labels, f.state, maxDuplicateCandidates)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	infos := s.toIssueInfos(q.Repo, f.mapping, f.registryLoaded, issues)
+	preThreshold := titlePreThreshold(threshold)
+	titleTokens := textTokens(doc.title)
+	result = []DuplicateCandidate{}
+	var failed []string
+	var firstErr error
+	for i := range issues {
+		if issues[i].Number == q.Exclude {
+			continue
+		}
+		if !isPackType(q.Type) && diceCoefficient(titleTokens, textTokens(issues[i].Title)) < preThreshold {
+			continue
+		}
+		other, err := s.readDupDoc(&issues[i])
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("#%d", issues[i].Number))
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		score, reason := similarity(q.Type, doc, other)
+		if score < threshold {
+			continue
+		}
+		result = append(result, DuplicateCandidate{
+			Number: infos[i].Number,
+			Title:  infos[i].Title,
+			URL:    infos[i].URL,
+			Status: infos[i].Status,
+			Score:  roundScore(score),
+			Reason: reason,
+		})
+	}
+	if len(failed) > 0 {
+		warnings = append(warnings, fmt.Sprintf("读取 %s 的 Issue 包失败，重复检查只比较了标题: %v", strings.Join(failed, ", "), firstErr))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Score > result[j].Score })
+	return result, warnings, nil
+}
+
+// isPackType 是否为按关键字段精确匹配重复的包类型
+func isPackType(issueType models.IssueType) bool {
+	return issueType == models.TypePackRegister || issueType == models.TypePackSync
+}
+
+// packSearchTerms 包类型查找重复候选的搜索关键字：仓库名和包名
+func packSearchTerms(doc dupDoc) []string {
+	repo := normalizeRepoURL(doc.field("repository"))
+	var terms []string
+	for _, term := range []string{repo[strings.LastIndex(repo, "/")+1:], strings.ToLower(doc.field("name"))} {
+		term = strings.ReplaceAll(term, `"`, "")
+		if quoted := fmt.Sprintf("%q", term); term != "" && (len(terms) == 0 || terms[0] != quoted) {
+			terms = append(terms, quoted)
+		}
+	}
+	return terms
+}
+
+// titlePreThreshold 模糊匹配时候选 Issue 的最低标题相似度
+//
+// 相似度为 0.6*标题 + 0.4*描述（没有描述时为标题相似度），标题相似度低于
+// (threshold-0.4)/0.6 时总分不可能达到 threshold。
+func titlePreThreshold(threshold float64) float64 {
+	return (threshold - 0.4) / 0.6
+}
+
+// readDupDoc 读取 Issue 的 Issue 包构建比较内容，没有包或读取失败时只比较标题
+func (s *IssueService) readDupDoc(issue *github.Issue) (dupDoc, error) {
+	pkg, _, err := s.readIssuePackage(issue)
+	if pkg == nil {
+		return newDupDoc(issue.Title, nil), err
+	}
+	return newDupDoc(issue.Title, pkg.Payload), nil
+}
+
+func roundScore(score float64) float64 {
+	return float64(int(score*100+0.5)) / 100
+}
+
+// DuplicateGroup 一组重复的 Issue：Canonical 为最早创建的 Issue
+type DuplicateGroup struct {
+	Type       string               `json:"type"`
+	Canonical  DuplicateCandidate   `json:"canonical"`
+	Duplicates []DuplicateCandidate `json:"duplicates"`
+}
+
+// DedupeReport 重复扫描结果：Skipped 为读取 Issue 包失败、未参与比较的 Issue
+type DedupeReport struct {
+	Groups  []DuplicateGroup     `json:"groups"`
+	Skipped []DuplicateCandidate `json:"skipped"`
+}
+
+// DedupeOptions 扫描重复 Issue 的选项
+type DedupeOptions struct {
+	Repo      string
+	Type      string  // 只扫描该类型，为空时扫描全部类型
+	Threshold float64 // 为 0 时使用 DefaultDuplicateThreshold
+	Close     bool    // 以 duplicate 关闭重复 Issue 并链接规范 Issue
+	Comment   string  // 关闭时附加的说明
+	DryRun    bool    // 只报告分组，不关闭
+}
+
+// Dedupe 扫描仓库中打开的 Issue，将相似的同类型 Issue 分组
+//
+// 每组以编号最小（最早创建）的 Issue 为规范 Issue，其余 Issue 与它的相似度不低于阈值。
+// Close 为 true 且非 DryRun 时，将每组中的重复 Issue 以 duplicate 关闭。
+// Issue 包读取失败的 Issue 不参与分组（避免只按标题误关闭），列在 Skipped 中。
+func (s *IssueService) Dedupe(opts DedupeOptions) (*DedupeReport, error) {
+	owner, repo, err := parseRepo(opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = DefaultDuplicateThreshold
+	}
+
+	f := s.listFilter(ListOptions{Repo: opts.Repo, Type: opts.Type})
+	issues, err := s.client.ListIssues(owner, repo, f.labels, f.state, 0)
+	if err != nil {
+		return nil, err
+	}
+	infos := s.toIssueInfos(opts.Repo, f.mapping, f.registryLoaded, issues)
+
+	// 按编号升序，使较早的 Issue 成为规范 Issue
+	order := make([]int, len(issues))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return issues[order[a]].Number < issues[order[b]].Number })
+
+	report := &DedupeReport{Groups: []DuplicateGroup{}, Skipped: []DuplicateCandidate{}}
+	docs := make([]dupDoc, len(issues))
+	skipped := make(map[int]bool)
+	for _, i := range order {
+		if infos[i].Type == "" {
+			continue
+		}
+		doc, err := s.readDupDoc(&issues[i])
+		if err != nil {
+			skipped[i] = true
+			report.Skipped = append(report.Skipped, DuplicateCandidate{
+				Number: infos[i].Number,
+				Title:  infos[i].Title,
+				URL:    infos[i].URL,
+				Status: infos[i].Status,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		docs[i] = doc
+	}
+
+	grouped := make(map[int]bool)
+	for x, i := range order {
+		if grouped[i] || skipped[i] || infos[i].Type == "" {
+			continue
+		}
+		group := DuplicateGroup{
+			Type:      infos[i].Type,
+			Canonical: DuplicateCandidate{Number: infos[i].Number, Title: infos[i].Title, URL: infos[i].URL, Status: infos[i].Status},
+		}
+		for _, j := range order[x+1:] {
+			if grouped[j] || skipped[j] || infos[j].Type != infos[i].Type {
+				continue
+			}
+			score, reason := similarity(models.IssueType(infos[i].Type), docs[i], docs[j])
+			if score < threshold {
+				continue
+			}
+			grouped[j] = true
+			group.Duplicates = append(group.Duplicates, DuplicateCandidate{
+				Number: infos[j].Number,
+				Title:  infos[j].Title,
+				URL:    infos[j].URL,
+				Status: infos[j].Status,
+				Score:  roundScore(score),
+				Reason: reason,
+			})
+		}
+		if len(group.Duplicates) == 0 {
+			continue
+		}
+		if opts.Close && !opts.DryRun {
+			for k := range group.Duplicates {
+				d := &group.Duplicates[k]
+				if err := s.CloseDuplicate(opts.Repo, d.Number, group.Canonical.Number, opts.Comment); err != nil {
+					d.Error = err.Error()
+					continue
+				}
+				d.Closed = true
+			}
+		}
+		report.Groups = append(report.Groups, group)
+	}
+	return report, nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+	"github.com/shichao402/github-issue-pack/internal/models"
+)
+
+func TestTextTokens(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Login fails on Windows 11", []string{"11", "fails", "login", "on", "windows"}},
+		{"a b-c x", nil},
+		// 每段中文的首字单独成词，单字词也能参与比较
+		{"登录失败", []string{"失败", "录失", "登", "登录"}},
+		{"点击 OK 崩溃", []string{"ok", "崩", "崩溃", "点", "点击"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for token := range textTokens(tt.in) {
+			got = append(got, token)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("textTokens(%q) = %q, 期望 %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	doc := func(title string, payload map[string]interface{}) dupDoc {
+		return newDupDoc(title, payload)
+	}
+
+	tests := []struct {
+		name       string
+		issueType  models.IssueType
+		a, b       dupDoc
+		want       float64
+		wantReason string
+	}{
+		{
+			name:      "pack-register 仓库地址相同",
+			issueType: models.TypePackRegister,
+			a:         doc("", map[string]interface{}{"repository": "https://github.com/Org/Pack.git", "name": "a"}),
+			b:         doc("", map[string]interface{}{"repository": "git@github.com:org/pack", "name": "b"}),
+			want:      1, wantReason: "repository 相同",
+		},
+		{
+			name:      "pack-register 包名相同",
+			issueType: models.TypePackRegister,
+			a:         doc("", map[string]interface{}{"repository": "org/a", "name": "Pack"}),
+			b:         doc("", map[string]interface{}{"repository": "org/b", "name": "pack"}),
+			want:      0.8, wantReason: "包名相同",
+		},
+		{
+			name:      "pack-register 空字段不算相同",
+			issueType: models.TypePackRegister,
+			a:         doc("", map[string]interface{}{}),
+			b:         doc("", map[string]interface{}{}),
+			want:      0,
+		},
+		{
+			name:      "pack-sync 仓库和版本相同",
+			issueType: models.TypePackSync,
+			a:         doc("", map[string]interface{}{"repository": "org/pack", "version": "v1.2.0"}),
+			b:         doc("", map[string]interface{}{"repository": "https://github.com/org/pack", "version": "1.2.0"}),
+			want:      1, wantReason: "repository 和 version 相同",
+		},
+		{
+			name:      "pack-sync 版本不同",
+			issueType: models.TypePackSync,
+			a:         doc("", map[string]interface{}{"repository": "org/pack", "version": "1.2.0"}),
+			b:         doc("", map[string]interface{}{"repository": "org/pack", "version": "1.3.0"}),
+			want:      0,
+		},
+		{
+			name:      "只比较标题",
+			issueType: models.TypeBugReport,
+			a:         doc("Login fails on Windows", nil),
+			b:         doc("login fails on macOS", map[string]interface{}{"description": "x"}),
+			want:      0.75, wantReason: "标题相似度 0.75",
+		},
+		{
+			name:      "payload 中的 title 优先",
+			issueType: models.TypeFeatureRequest,
+			a:         doc("外部标题", map[string]interface{}{"title": "dark mode"}),
+			b:         doc("dark mode", nil),
+			want:      1, wantReason: "标题相似度 1.00",
+		},
+		{
+			name:      "标题和描述加权",
+			issueType: models.TypeBugReport,
+			a:         doc("crash on start", map[string]interface{}{"description": "app crashes immediately"}),
+			b:         doc("crash on start", map[string]interface{}{"description": "totally different text"}),
+			want:      0.6, wantReason: "标题相似度 1.00，描述相似度 0.00",
+		},
+	}
+	for _, tt := range tests {
+		got, reason := similarity(tt.issueType, tt.a, tt.b)
+		if math.Abs(got-tt.want) > 1e-9 || reason != tt.wantReason {
+			t.Errorf("%s: similarity = (%v, %q), 期望 (%v, %q)", tt.name, got, reason, tt.want, tt.wantReason)
+		}
+	}
+}
+
+func TestDedupeSkipsUnreadablePackages(t *testing.T) {
+	gistURL := func(id string) string { return "https://gist.github.com/me/" + id }
+	labels := []github.Label{{Name: LabelCursorToolset}, {Name: "bug-report"}, {Name: "pending"}}
+	issues := []github.Issue{
+		{Number: 3, Title: "login crash on start", State: "open", CreatedAt: "2024-01-01T00:00:00Z", Labels: labels, Body: buildIssueBody("bug-report", "x", "", gistURL("cc03"))},
+		{Number: 2, Title: "login crash on start", State: "open", CreatedAt: "2024-01-01T00:00:00Z", Labels: labels, Body: buildIssueBody("bug-report", "x", "", gistURL("bb02"))},
+		{Number: 1, Title: "login crash on start", State: "open", CreatedAt: "2024-01-01T00:00:00Z", Labels: labels, Body: buildIssueBody("bug-report", "x", "", gistURL("aa01"))},
+	}
+	pkg := func(description string) string {
+		data, _ := json.Marshal(map[string]interface{}{
+			"$schema": models.SchemaVersion,
+			"type":    "bug-report",
+			"payload": map[string]string{"title": "login crash on start", "description": description},
+		})
+		return string(data)
+	}
+	gists := map[string]string{
+		"aa01": pkg("app crashes after login button"),
+		"cc03": pkg("app crashes after login button"),
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/o/r/issues":
+			json.NewEncoder(w).Encode(issues)
+		case strings.HasPrefix(r.URL.Path, "/gists/"):
+			content, ok := gists[strings.TrimPrefix(r.URL.Path, "/gists/")]
+			if !ok {
+				http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(github.Gist{Files: map[string]github.GistFile{PayloadFileName: {Content: content}}})
+		default:
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	svc := NewIssueServiceWithConfig(Config{Token: "token", APIHost: srv.URL})
+	report, err := svc.Dedupe(DedupeOptions{Repo: "o/r", Close: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Skipped) != 1 || report.Skipped[0].Number != 2 || report.Skipped[0].Error == "" {
+		t.Errorf("Skipped = %+v, 期望只跳过 #2 并说明原因", report.Skipped)
+	}
+	if len(report.Groups) != 1 {
+		t.Fatalf("Groups = %+v, 期望 1 组", report.Groups)
+	}
+	g := report.Groups[0]
+	if g.Canonical.Number != 1 || len(g.Duplicates) != 1 || g.Duplicates[0].Number != 3 {
+		t.Errorf("分组 = #%d %+v, 期望 #1 与 #3 重复且不包含 #2", g.Canonical.Number, g.Duplicates)
+	}
+}
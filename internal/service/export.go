@@ -0,0 +1,159 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/models"
+)
+
+// ExportOptions 导出选项
+type ExportOptions struct {
+	Repo   string
+	Output string // 归档路径：.tar.gz/.tgz 为压缩包，否则为目录
+	Status string // 状态过滤，默认 all
+	Type   string
+}
+
+// ExportReport 导出结果
+type ExportReport struct {
+	Repo        string `json:"repo"`
+	Output      string `json:"output"`
+	Issues      int    `json:"issues"`
+	Packages    int    `json:"packages"`
+	Attachments int    `json:"attachments"`
+	Comments    int    `json:"comments"`
+	Missing     []int  `json:"missing_packages,omitempty"` // 引用的 Issue 包无法读取的 Issue
+}
+
+// Export 将仓库中的标准化 Issue、评论和解包后的 Issue 包导出到归档
+func (s *IssueService) Export(opts ExportOptions) (*ExportReport, error) {
+	owner, repo, err := parseRepo(opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Status == "" {
+		opts.Status = "all"
+	}
+
+	f := s.listFilter(ListOptions{Repo: opts.Repo, Status: opts.Status, Type: opts.Type})
+	issues, err := s.client.ListIssues(owner, repo, f.labels, f.state, 0)
+	if err != nil {
+		return nil, err
+	}
+	infos := s.toIssueInfos(opts.Repo, f.mapping, f.registryLoaded, issues)
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Number < issues[j].Number })
+	sort.SliceStable(infos, func(i, j int) bool { return infos[i].Number < infos[j].Number })
+
+	w, err := createArchive(opts.Output)
+	if err != nil {
+		return nil, err
+	}
+	defer w.Close()
+
+	report := &ExportReport{Repo: opts.Repo, Output: opts.Output}
+	var lines []string
+	types := map[string]bool{}
+	for i := range issues {
+		issue := &issues[i]
+		s.progress.report(fmt.Sprintf("导出 #%d", issue.Number))
+
+		item := ArchivedIssue{
+			Number:    issue.Number,
+			Title:     issue.Title,
+			State:     issue.State,
+			Type:      infos[i].Type,
+			Status:    infos[i].Status,
+			Labels:    []string{},
+			Author:    issue.User.Login,
+			CreatedAt: issue.CreatedAt,
+			ClosedAt:  issue.ClosedAt,
+			URL:       issue.HTMLURL,
+			Body:      issue.Body,
+			GistURL:   extractGistURL(issue.Body),
+		}
+		for _, label := range issue.Labels {
+			item.Labels = append(item.Labels, label.Name)
+		}
+		if item.Type != "" {
+			types[item.Type] = true
+		}
+
+		item.Comments, err = s.Comments(opts.Repo, issue.Number)
+		if err != nil {
+			return nil, fmt.Errorf("获取 #%d 的评论失败: %w", issue.Number, err)
+		}
+		report.Comments += len(item.Comments)
+
+		if item.GistURL != "" {
+			pkg, _ := s.issuePackage(issue)
+			if pkg == nil {
+				report.Missing = append(report.Missing, issue.Number)
+			} else {
+				if err := writeArchivedPackage(w, issue.Number, pkg); err != nil {
+					return nil, err
+				}
+				item.Package = archivePackagePath(issue.Number)
+				report.Packages++
+				report.Attachments += len(pkg.Attachments)
+			}
+		}
+
+		line, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("序列化 #%d 失败: %w", issue.Number, err)
+		}
+		lines = append(lines, string(line))
+	}
+
+	var typeNames []string
+	for t := range types {
+		typeNames = append(typeNames, t)
+	}
+	manifest := ArchiveManifest{
+		Format:      ArchiveFormat,
+		Repo:        opts.Repo,
+		ExportedAt:  time.Now().UTC().Format(time.RFC3339),
+		ToolVersion: models.ToolVersion,
+		Issues:      len(lines),
+		Labels:      f.mapping.Specs(typeNames),
+	}
+	data, _ := json.MarshalIndent(manifest, "", "  ")
+	if err := w.WriteFile(archiveManifestFile, append(data, '\n')); err != nil {
+		return nil, err
+	}
+	jsonl := strings.Join(lines, "\n")
+	if jsonl != "" {
+		jsonl += "\n"
+	}
+	if err := w.WriteFile(archiveIssuesFile, []byte(jsonl)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("写入归档失败: %w", err)
+	}
+
+	report.Issues = len(lines)
+	return report, nil
+}
+
+// writeArchivedPackage 写入 Issue 包及其附件
+func writeArchivedPackage(w archiveWriter, number int, pkg *models.IssuePackage) error {
+	pkgJSON, err := pkg.ToJSON()
+	if err != nil {
+		return fmt.Errorf("序列化 #%d 的 Issue 包失败: %w", number, err)
+	}
+	if err := w.WriteFile(archivePackagePath(number), []byte(pkgJSON)); err != nil {
+		return err
+	}
+	paths := archiveAttachmentPaths(number, pkg.Attachments)
+	for i, att := range pkg.Attachments {
+		if err := w.WriteFile(paths[i], []byte(att.Content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+	"github.com/shichao402/github-issue-pack/internal/models"
+)
+
+// orphanGracePeriod 孤立 Gist 的保护期
+//
+// Create 先创建 Gist 再创建 Issue，刚创建的 Gist 可能尚未被 Issue 引用。
+const orphanGracePeriod = time.Hour
+
+// GCAction 清理动作
+type GCAction string
+
+const (
+	GCKeep         GCAction = "keep"
+	GCDeleteOrphan GCAction = "delete-orphan"
+	GCDeleteStale  GCAction = "delete-stale"
+	GCSkip         GCAction = "skip"
+)
+
+// GCOptions 清理 Gist 的选项
+type GCOptions struct {
+	Repo       string        // 仅处理目标为该仓库的 Gist（可选）
+	Retention  time.Duration // Issue 关闭超过该时长后清理其 Gist
+	ArchiveDir string        // 删除前归档到该目录（可选）
+	DryRun     bool
+}
+
+// GCItem 单个 Gist 的清理结果
+type GCItem struct {
+	GistID      string   `json:"gist_id"`
+	GistURL     string   `json:"gist_url"`
+	Description string   `json:"description"`
+	TargetRepo  string   `json:"target_repo,omitempty"`
+	IssueNum    int      `json:"issue_number,omitempty"`
+	IssueState  string   `json:"issue_state,omitempty"`
+	ClosedAt    string   `json:"closed_at,omitempty"`
+	Action      GCAction `json:"action"`
+	Reason      string   `json:"reason"`
+	ArchivedTo  string   `json:"archived_to,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// GCReport 清理报告
+type GCReport struct {
+	DryRun   bool     `json:"dry_run"`
+	Scanned  int      `json:"scanned"`
+	Deleted  int      `json:"deleted"`
+	Archived int      `json:"archived"`
+	Items    []GCItem `json:"items"`
+}
+
+// gistDescriptionPattern 本工具创建的 Gist 描述格式: "[type] title"
+var gistDescriptionPattern = regexp.MustCompile(`^\[[a-z0-9-]+\] `)
+
+// GC 清理孤立的和已过期的 payload Gist
+//
+// 孤立 Gist 指目标仓库中没有任何 Issue 正文引用的 Gist（通常是 Issue 创建失败遗留的）；
+// 过期 Gist 指其 Issue 已关闭超过保留期的 Gist。
+func (s *IssueService) GC(opts GCOptions) (*GCReport, error) {
+	gists, err := s.client.ListGists()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &GCReport{DryRun: opts.DryRun}
+	issueIndex := make(map[string]map[string]*github.Issue)
+	indexErrors := make(map[string]error)
+
+	for _, g := range gists {
+		if !gistDescriptionPattern.MatchString(g.Description) {
+			continue
+		}
+		if _, ok := g.Files[PayloadFileName]; !ok {
+			continue
+		}
+
+		gist, err := s.client.GetGist(g.ID)
+		if err != nil {
+			report.Items = append(report.Items, GCItem{
+				GistID:      g.ID,
+				GistURL:     g.HTMLURL,
+				Description: g.Description,
+				Action:      GCSkip,
+				Reason:      "无法读取 Gist 内容",
+				Error:       err.Error(),
+			})
+			continue
+		}
+
+		pkg, err := models.ParseIssuePackage(gist.Files[PayloadFileName].Content)
+		if err != nil || pkg.Schema != models.SchemaVersion {
+			continue
+		}
+		if opts.Repo != "" && pkg.Target.Repo != opts.Repo {
+			continue
+		}
+
+		report.Scanned++
+		item := GCItem{
+			GistID:      gist.ID,
+			GistURL:     gist.HTMLURL,
+			Description: gist.Description,
+			TargetRepo:  pkg.Target.Repo,
+		}
+
+		index, ok := issueIndex[pkg.Target.Repo]
+		if !ok && indexErrors[pkg.Target.Repo] == nil {
+			index, err = s.indexIssuesByGist(pkg.Target.Repo)
+			if err != nil {
+				indexErrors[pkg.Target.Repo] = err
+			} else {
+				issueIndex[pkg.Target.Repo] = index
+			}
+		}
+		// 无法确认是否仍被引用时不删除
+		if err := indexErrors[pkg.Target.Repo]; err != nil {
+			item.Action = GCSkip
+			item.Reason = "无法列出目标仓库的 Issue，跳过"
+			item.Error = err.Error()
+			report.Items = append(report.Items, item)
+			continue
+		}
+
+		classifyGist(&item, gist, index[gist.ID], opts.Retention)
+
+		if item.Action == GCDeleteOrphan || item.Action == GCDeleteStale {
+			if !opts.DryRun {
+				if err := s.removeGist(&item, gist, opts.ArchiveDir); err != nil {
+					item.Error = err.Error()
+				} else {
+					report.Deleted++
+					if item.ArchivedTo != "" {
+						report.Archived++
+					}
+				}
+			}
+		}
+
+		report.Items = append(report.Items, item)
+	}
+
+	return report, nil
+}
+
+// payloadLinkPattern Issue 正文中指向包 Gist 的链接，见 buildIssueBody
+var payloadLinkPattern = regexp.MustCompile(`\[View full payload\]\((https://[^)\s]+)\)`)
+
+// gistIDPattern Issue 正文中可能是 Gist ID 的十六进制串（Gist 链接中的 ID 也会匹配）
+var gistIDPattern = regexp.MustCompile(`\b[a-f0-9]{20,40}\b`)
+
+// indexIssuesByGist 列出仓库中的全部 Issue，并按正文引用的 Gist ID 建立索引
+//
+// 不按标记标签过滤：标签体系调整、仓库级标签覆盖或手动移除标签后，Issue 仍可能引用 Gist。
+// 以包链接引用 Gist 的 Issue 优先，其余只是在正文中提到其 ID 的 Issue 也视为引用。
+func (s *IssueService) indexIssuesByGist(repoStr string) (map[string]*github.Issue, error) {
+	owner, repo, err := parseRepo(repoStr)
+	if err != nil {
+		return nil, err
+	}
+
+	issues, err := s.client.ListIssues(owner, repo, nil, "all", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]*github.Issue)
+	for i := range issues {
+		if m := payloadLinkPattern.FindStringSubmatch(issues[i].Body); m != nil {
+			if gistID := extractGistID(m[1]); gistID != "" {
+				index[gistID] = &issues[i]
+			}
+		}
+	}
+	for i := range issues {
+		for _, id := range gistIDPattern.FindAllString(issues[i].Body, -1) {
+			if _, ok := index[id]; !ok {
+				index[id] = &issues[i]
+			}
+		}
+	}
+	return index, nil
+}
+
+// classifyGist 根据关联 Issue 判断 Gist 的清理动作
+func classifyGist(item *GCItem, gist *github.Gist, issue *github.Issue, retention time.Duration) {
+	now := time.Now()
+
+	if issue == nil {
+		created, err := time.Parse(time.RFC3339, gist.CreatedAt)
+		if err == nil && now.Sub(created) < orphanGracePeriod {
+			item.Action = GCKeep
+			item.Reason = "未被 Issue 引用，但创建时间过短，可能仍在创建中"
+			return
+		}
+		item.Action = GCDeleteOrphan
+		item.Reason = "没有 Issue 引用此 Gist"
+		return
+	}
+
+	item.IssueNum = issue.Number
+	item.IssueState = issue.State
+	item.ClosedAt = issue.ClosedAt
+
+	if issue.State != "closed" {
+		item.Action = GCKeep
+		item.Reason = "Issue 仍处于打开状态"
+		return
+	}
+
+	closed, err := time.Parse(time.RFC3339, issue.ClosedAt)
+	if err != nil {
+		item.Action = GCKeep
+		item.Reason = "无法确定 Issue 关闭时间"
+		return
+	}
+	if now.Sub(closed) < retention {
+		item.Action = GCKeep
+		item.Reason = fmt.Sprintf("Issue 关闭未满保留期 (%s)", retention)
+		return
+	}
+
+	item.Action = GCDeleteStale
+	item.Reason = fmt.Sprintf("Issue 已关闭超过保留期 (%s)", retention)
+}
+
+// removeGist 归档（可选）并删除 Gist
+func (s *IssueService) removeGist(item *GCItem, gist *github.Gist, archiveDir string) error {
+	if archiveDir != "" {
+		dir := filepath.Join(archiveDir, gist.ID)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("创建归档目录失败: %w", err)
+		}
+		for name, file := range gist.Files {
+			path := filepath.Join(dir, filepath.Base(name))
+			if err := os.WriteFile(path, []byte(file.Content), 0644); err != nil {
+				return fmt.Errorf("归档文件失败 %s: %w", name, err)
+			}
+		}
+		item.ArchivedTo = dir
+	}
+
+	return s.client.DeleteGist(gist.ID)
+}
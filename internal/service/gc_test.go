@@ -0,0 +1,124 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+)
+
+func TestClassifyGist(t *testing.T) {
+	now := time.Now().UTC()
+	ago := func(d time.Duration) string { return now.Add(-d).Format(time.RFC3339) }
+	retention := 30 * 24 * time.Hour
+
+	tests := []struct {
+		name  string
+		gist  github.Gist
+		issue *github.Issue
+		want  GCAction
+	}{
+		{
+			name: "孤立",
+			gist: github.Gist{CreatedAt: ago(2 * time.Hour)},
+			want: GCDeleteOrphan,
+		},
+		{
+			name: "孤立但仍在保护期内",
+			gist: github.Gist{CreatedAt: ago(10 * time.Minute)},
+			want: GCKeep,
+		},
+		{
+			name: "孤立且创建时间无法解析",
+			gist: github.Gist{CreatedAt: "bad"},
+			want: GCDeleteOrphan,
+		},
+		{
+			name:  "Issue 打开",
+			gist:  github.Gist{CreatedAt: ago(90 * 24 * time.Hour)},
+			issue: &github.Issue{Number: 1, State: "open"},
+			want:  GCKeep,
+		},
+		{
+			name:  "关闭未满保留期",
+			issue: &github.Issue{Number: 2, State: "closed", ClosedAt: ago(29 * 24 * time.Hour)},
+			want:  GCKeep,
+		},
+		{
+			name:  "关闭超过保留期",
+			issue: &github.Issue{Number: 3, State: "closed", ClosedAt: ago(31 * 24 * time.Hour)},
+			want:  GCDeleteStale,
+		},
+		{
+			name:  "关闭时间无法解析",
+			issue: &github.Issue{Number: 4, State: "closed"},
+			want:  GCKeep,
+		},
+	}
+	for _, tt := range tests {
+		var item GCItem
+		classifyGist(&item, &tt.gist, tt.issue, retention)
+		if item.Action != tt.want {
+			t.Errorf("%s: Action = %s (%s), 期望 %s", tt.name, item.Action, item.Reason, tt.want)
+		}
+		if tt.issue != nil && item.IssueNum != tt.issue.Number {
+			t.Errorf("%s: IssueNum = %d, 期望 %d", tt.name, item.IssueNum, tt.issue.Number)
+		}
+	}
+}
+
+func TestIndexIssuesByGist(t *testing.T) {
+	const (
+		tagged   = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		untagged = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+		mention  = "cccccccccccccccccccccccccccccccc"
+	)
+	issues := []github.Issue{
+		{Number: 1, State: "open", Labels: []github.Label{{Name: LabelCursorToolset}},
+			Body: buildIssueBody("bug-report", "a", "", "https://gist.github.com/me/"+tagged)},
+		// 标记标签被手动移除或标签体系已调整
+		{Number: 2, State: "closed", Body: buildIssueBody("bug-report", "b", "", "https://gist.github.com/me/"+untagged)},
+		{Number: 3, State: "open", Body: "与 gist " + mention + " 重复，另见 https://gist.github.com/me/" + tagged},
+	}
+
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		json.NewEncoder(w).Encode(issues)
+	}))
+	defer srv.Close()
+
+	svc := NewIssueServiceWithConfig(Config{Token: "token", APIHost: srv.URL})
+	index, err := svc.indexIssuesByGist("o/r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q, _ := url.ParseQuery(query); q.Get("labels") != "" || q.Get("state") != "all" {
+		t.Errorf("应列出全部状态的 Issue 且不按标签过滤: %s", query)
+	}
+
+	for id, want := range map[string]int{tagged: 1, untagged: 2, mention: 3} {
+		if issue := index[id]; issue == nil || issue.Number != want {
+			t.Errorf("Gist %s 对应 Issue %v, 期望 #%d", id[:4], issue, want)
+		}
+	}
+	if index["dddddddddddddddddddddddddddddddd"] != nil {
+		t.Error("未被引用的 Gist 不应出现在索引中")
+	}
+}
+
+func TestIndexIssuesByGistError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Bad credentials"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	svc := NewIssueServiceWithConfig(Config{Token: "token", APIHost: srv.URL})
+	if _, err := svc.indexIssuesByGist("o/r"); err == nil {
+		t.Fatal("列出 Issue 失败时应返回错误，由 GC 跳过而不是删除")
+	}
+}
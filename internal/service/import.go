@@ -0,0 +1,335 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+	"github.com/shichao402/github-issue-pack/internal/models"
+)
+
+// importedMarkerPattern 导入的 Issue 在 body 开头记录原 Issue，重复导入时据此跳过
+var importedMarkerPattern = regexp.MustCompile(`<!-- github-issue:imported-from=([A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)#(\d+) -->`)
+
+// ImportAction 单个 Issue 的导入结果
+type ImportAction string
+
+const (
+	ImportCreated ImportAction = "created"
+	ImportSkipped ImportAction = "skipped" // 目标仓库中已有从同一 Issue 导入的 Issue
+	ImportResumed ImportAction = "resumed" // 补齐上次中断的导入：重放缺少的评论、关闭 Issue
+	ImportFailed  ImportAction = "failed"
+)
+
+// ImportOptions 导入选项
+type ImportOptions struct {
+	Archive string // export 生成的目录或 .tar.gz
+	Repo    string // 目标仓库
+	DryRun  bool
+}
+
+// ImportItem 单个 Issue 的导入结果
+type ImportItem struct {
+	Original   int          `json:"original"`
+	Title      string       `json:"title"`
+	Status     string       `json:"status"`
+	Number     int          `json:"number,omitempty"` // 目标仓库中的编号
+	URL        string       `json:"url,omitempty"`
+	Action     ImportAction `json:"action"`
+	Comments   int          `json:"comments"`
+	Error      string       `json:"error,omitempty"`
+	HasPackage bool         `json:"has_package"`
+	SourceURL  string       `json:"source_url"`
+}
+
+// ImportReport 导入结果
+type ImportReport struct {
+	Source string       `json:"source"` // 归档的来源仓库
+	Repo   string       `json:"repo"`
+	DryRun bool         `json:"dry_run"`
+	Items  []ImportItem `json:"items"`
+}
+
+// Import 将 export 生成的归档重放到目标仓库
+//
+// 按原编号顺序为每个 Issue 重新上传 Issue 包、创建 Issue 并依次发表原评论，
+// 保留类型和状态（已关闭的 Issue 导入后关闭），body 中链接原 Issue。
+// 目标仓库中已有从同一 Issue 导入的 Issue 时跳过；上次导入在创建 Issue 之后中断的，
+// 补齐缺少的评论和关闭，因此中断后可以重新执行。
+func (s *IssueService) Import(opts ImportOptions) (*ImportReport, error) {
+	owner, repo, err := parseRepo(opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := readArchive(opts.Archive)
+	if err != nil {
+		return nil, err
+	}
+	var manifest ArchiveManifest
+	if err := json.Unmarshal(files[archiveManifestFile], &manifest); err != nil {
+		return nil, fmt.Errorf("归档中没有有效的 %s", archiveManifestFile)
+	}
+	if manifest.Format != ArchiveFormat {
+		return nil, fmt.Errorf("不支持的归档格式: %s", manifest.Format)
+	}
+	issues, err := parseArchivedIssues(files[archiveIssuesFile])
+	if err != nil {
+		return nil, err
+	}
+
+	// 目标仓库中已导入的 Issue
+	l := s.labelsFor(opts.Repo)
+	existing, err := s.client.ListIssues(owner, repo, []string{l.Marker}, "all", 0)
+	if err != nil {
+		return nil, err
+	}
+	imported := map[string]github.Issue{}
+	for _, issue := range existing {
+		if m := importedMarkerPattern.FindStringSubmatch(issue.Body); m != nil {
+			imported[m[1]+"#"+m[2]] = issue
+		}
+	}
+
+	// 补齐目标仓库的标签，类型标签按归档中出现的类型创建
+	types := map[string]bool{}
+	for _, item := range issues {
+		if item.Type != "" {
+			types[item.Type] = true
+		}
+	}
+	var typeNames []string
+	for t := range types {
+		typeNames = append(typeNames, t)
+	}
+	if _, err := s.syncLabels(opts.Repo, l.Specs(typeNames), opts.DryRun); err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{Source: manifest.Repo, Repo: opts.Repo, DryRun: opts.DryRun, Items: []ImportItem{}}
+	for _, item := range issues {
+		result := ImportItem{
+			Original:   item.Number,
+			Title:      item.Title,
+			Status:     item.Status,
+			Comments:   len(item.Comments),
+			HasPackage: item.Package != "",
+			SourceURL:  item.URL,
+		}
+		if issue, ok := imported[fmt.Sprintf("%s#%d", manifest.Repo, item.Number)]; ok {
+			result.Action, result.Number, result.URL = ImportSkipped, issue.Number, issue.HTMLURL
+			if importIncomplete(issue, item) {
+				s.progress.report(fmt.Sprintf("补齐 #%d 的导入", item.Number))
+				result.Action = ImportResumed
+				if !opts.DryRun {
+					if err := s.resumeImport(opts.Repo, issue, item); err != nil {
+						result.Action, result.Error = ImportFailed, err.Error()
+					}
+				}
+			}
+			report.Items = append(report.Items, result)
+			continue
+		}
+
+		s.progress.report(fmt.Sprintf("导入 #%d", item.Number))
+		result.Action = ImportCreated
+		if !opts.DryRun {
+			if err := s.importIssue(opts.Repo, manifest.Repo, item, files, &result); err != nil {
+				result.Action, result.Error = ImportFailed, err.Error()
+			}
+		}
+		report.Items = append(report.Items, result)
+	}
+	return report, nil
+}
+
+// importIssue 在目标仓库中重建单个 Issue
+func (s *IssueService) importIssue(repoStr, source string, item ArchivedIssue, files map[string][]byte, result *ImportItem) error {
+	owner, repo, _ := parseRepo(repoStr)
+	l := s.labelsFor(repoStr)
+
+	var pkg *models.IssuePackage
+	if item.Package != "" {
+		data, ok := files[item.Package]
+		if !ok {
+			return fmt.Errorf("归档中缺少 %s", item.Package)
+		}
+		p, err := models.ParseIssuePackage(string(data))
+		if err != nil {
+			return fmt.Errorf("解析 Issue 包失败: %w", err)
+		}
+		for _, att := range p.Attachments {
+			if err := validateImportAttachmentName(att.Name); err != nil {
+				return err
+			}
+		}
+		p.Target.Repo = repoStr
+		pkg = p
+	}
+
+	body := item.Body
+	var gistID string
+	if pkg != nil {
+		pkgJSON, err := pkg.ToJSON()
+		if err != nil {
+			return fmt.Errorf("序列化 Issue 包失败: %w", err)
+		}
+		gistFiles := map[string]string{PayloadFileName: pkgJSON}
+		for _, att := range pkg.Attachments {
+			gistFiles[att.Name] = att.Content
+		}
+		gist, err := s.client.CreateGist(fmt.Sprintf("[%s] %s", pkg.Type, item.Title), false, gistFiles)
+		if err != nil {
+			return fmt.Errorf("创建 Gist 失败: %w", err)
+		}
+		gistID = gist.ID
+		// 摘要按目标仓库注册的 body 模板重新生成，类型未注册时省略
+		typeDef, _, _ := s.ResolveType(repoStr, pkg.Type)
+		summary, _ := renderBodyTemplate(typeDef, item.Title, pkg.Payload)
+		body = buildIssueBody(pkg.Type, item.Title, summary, gist.HTMLURL)
+	}
+	body = fmt.Sprintf("<!-- github-issue:imported-from=%s#%d -->\n> 📥 导入自 [%s#%d](%s)，原作者 `%s`，创建于 %s\n\n%s",
+		source, item.Number, source, item.Number, item.URL, item.Author, item.CreatedAt, body)
+
+	labels := importLabels(l, item)
+	issue, err := s.client.CreateIssue(owner, repo, item.Title, body, labels)
+	if err != nil {
+		if gistID != "" {
+			// 不留下没有 Issue 引用的 Gist，删除失败时不影响返回的错误
+			s.client.DeleteGist(gistID)
+		}
+		return fmt.Errorf("创建 Issue 失败: %w", err)
+	}
+	result.Number, result.URL = issue.Number, issue.HTMLURL
+
+	return s.completeImport(repoStr, issue, item, nil)
+}
+
+// validateImportAttachmentName 拒绝不能安全作为 Gist 文件名的附件名：
+// 空名称、只由点组成、含路径分隔符或控制字符，以及与 issue-payload.json 同名
+func validateImportAttachmentName(name string) error {
+	switch {
+	case strings.Trim(name, ". ") == "":
+		return fmt.Errorf("Issue 包中的附件名 %q 无效", name)
+	case strings.ContainsAny(name, "/\\"):
+		return fmt.Errorf("Issue 包中的附件名 %q 含路径分隔符", name)
+	case strings.IndexFunc(name, func(r rune) bool { return r < 0x20 || r == 0x7f }) >= 0:
+		return fmt.Errorf("Issue 包中的附件名 %q 含控制字符", name)
+	case name == PayloadFileName:
+		return fmt.Errorf("Issue 包中的附件名 %q 与 Issue 包文件同名", name)
+	}
+	return nil
+}
+
+// importLabels 导入的 Issue 的标签：标记、原状态和原类型
+//
+// 原 Issue 没有状态标签时：打开的 Issue 按新 Issue 标记为 pending，
+// 已关闭的 Issue 不加状态标签，避免关闭后仍显示为待处理。
+func importLabels(l Labels, item ArchivedIssue) []string {
+	labels := []string{l.Marker}
+	switch {
+	case item.Status != "":
+		labels = append(labels, l.Status(item.Status))
+	case item.State != "closed":
+		labels = append(labels, l.Pending)
+	}
+	if item.Type != "" {
+		labels = append(labels, l.Type(item.Type))
+	}
+	return labels
+}
+
+// importIncomplete 导入的 Issue 是否缺少评论，或原 Issue 已关闭而导入的 Issue 仍打开
+func importIncomplete(issue github.Issue, item ArchivedIssue) bool {
+	return issue.Comments < len(item.Comments) || (item.State == "closed" && issue.State != "closed")
+}
+
+// resumeImport 补齐上次中断的导入
+func (s *IssueService) resumeImport(repoStr string, issue github.Issue, item ArchivedIssue) error {
+	owner, repo, _ := parseRepo(repoStr)
+	comments, err := s.client.ListComments(owner, repo, issue.Number)
+	if err != nil {
+		return fmt.Errorf("获取已导入的评论失败: %w", err)
+	}
+	return s.completeImport(repoStr, &issue, item, comments)
+}
+
+// completeImport 依次发表 existing 中还没有的原评论，原 Issue 已关闭时关闭导入的 Issue
+func (s *IssueService) completeImport(repoStr string, issue *github.Issue, item ArchivedIssue, existing []github.Comment) error {
+	owner, repo, _ := parseRepo(repoStr)
+	for _, c := range item.Comments {
+		if commentReplayed(existing, c) {
+			continue
+		}
+		if err := s.client.AddComment(owner, repo, issue.Number, replayComment(c)); err != nil {
+			return fmt.Errorf("重放评论失败: %w", err)
+		}
+	}
+
+	if item.State == "closed" && issue.State != "closed" {
+		labels := importLabels(s.labelsFor(repoStr), item)
+		if _, err := s.client.UpdateIssue(owner, repo, issue.Number, "closed", labels); err != nil {
+			return fmt.Errorf("关闭 Issue 失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// commentReplayed 原评论是否已重放：重放的评论以注明原作者和时间的引用开头
+func commentReplayed(existing []github.Comment, c ThreadComment) bool {
+	header := replayHeader(c)
+	for _, e := range existing {
+		if strings.Contains(e.Body, header) {
+			return true
+		}
+	}
+	return false
+}
+
+// replayComment 重放原评论：注明原作者和时间，状态评论和催促评论保留标记
+func replayComment(c ThreadComment) string {
+	body := replayHeader(c)
+	if c.URL != "" {
+		body += fmt.Sprintf("（[原评论](%s)）", c.URL)
+	}
+	body += "\n\n" + c.Body
+	switch {
+	case c.Status != "":
+		return statusComment(c.Status, body)
+	case c.Reminder:
+		return reminderMarker + "\n" + body
+	}
+	return body
+}
+
+// replayHeader 重放评论开头注明原作者和时间的引用
+func replayHeader(c ThreadComment) string {
+	return fmt.Sprintf("> 💬 `%s` 于 %s 评论", c.Author, c.CreatedAt)
+}
+
+// parseArchivedIssues 解析 issues.jsonl，按原编号升序返回
+func parseArchivedIssues(data []byte) ([]ArchivedIssue, error) {
+	var issues []ArchivedIssue
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var item ArchivedIssue
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			return nil, fmt.Errorf("解析 %s 第 %d 行失败: %w", archiveIssuesFile, line, err)
+		}
+		issues = append(issues, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取 %s 失败: %w", archiveIssuesFile, err)
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Number < issues[j].Number })
+	return issues, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseArchivedIssues(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantNumbers []int
+		wantErr     string
+	}{
+		{
+			name:        "按编号排序并跳过空行",
+			data:        "{\"number\": 12, \"title\": \"b\"}\n\n  \n{\"number\": 3, \"title\": \"a\", \"comments\": [{\"author\": \"x\", \"body\": \"hi\"}]}\n",
+			wantNumbers: []int{3, 12},
+		},
+		{
+			name:        "最后一行没有换行",
+			data:        `{"number": 1}`,
+			wantNumbers: []int{1},
+		},
+		{
+			name: "空文件",
+			data: "",
+		},
+		{
+			name:    "报告出错的行号",
+			data:    "{\"number\": 1}\n\n{\"number\": \n",
+			wantErr: "第 3 行",
+		},
+		{
+			name:        "超过默认缓冲区的长行",
+			data:        `{"number": 7, "body": "` + strings.Repeat("x", 200*1024) + `"}`,
+			wantNumbers: []int{7},
+		},
+	}
+	for _, tt := range tests {
+		issues, err := parseArchivedIssues([]byte(tt.data))
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: 错误 = %v, 期望包含 %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: parseArchivedIssues 报错: %v", tt.name, err)
+			continue
+		}
+		var numbers []int
+		for _, issue := range issues {
+			numbers = append(numbers, issue.Number)
+		}
+		if len(numbers) != len(tt.wantNumbers) {
+			t.Errorf("%s: 编号 = %v, 期望 %v", tt.name, numbers, tt.wantNumbers)
+			continue
+		}
+		for i := range numbers {
+			if numbers[i] != tt.wantNumbers[i] {
+				t.Errorf("%s: 编号 = %v, 期望 %v", tt.name, numbers, tt.wantNumbers)
+				break
+			}
+		}
+	}
+}
+
+func TestParseArchivedIssuesComments(t *testing.T) {
+	issues, err := parseArchivedIssues([]byte(`{"number": 5, "comments": [{"author": "alice", "body": "hi"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || len(issues[0].Comments) != 1 || issues[0].Comments[0].Author != "alice" {
+		t.Errorf("评论解析结果不正确: %+v", issues)
+	}
+}
+
+func TestValidateImportAttachmentName(t *testing.T) {
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"logs.txt", true},
+		{".env", true},
+		{"", false},
+		{"..", false},
+		{"../etc/passwd", false},
+		{`a\b.txt`, false},
+		{"a\x00b", false},
+		{PayloadFileName, false},
+	}
+	for _, tt := range tests {
+		if err := validateImportAttachmentName(tt.name); (err == nil) != tt.ok {
+			t.Errorf("validateImportAttachmentName(%q) = %v, 期望通过 %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestImportLabels(t *testing.T) {
+	l := DefaultLabels()
+	tests := []struct {
+		name string
+		item ArchivedIssue
+		want []string
+	}{
+		{
+			name: "保留原状态和类型",
+			item: ArchivedIssue{State: "closed", Status: "processed", Type: "bug-report"},
+			want: []string{l.Marker, l.Processed, l.Type("bug-report")},
+		},
+		{
+			name: "打开且没有状态时为 pending",
+			item: ArchivedIssue{State: "open"},
+			want: []string{l.Marker, l.Pending},
+		},
+		{
+			name: "关闭且没有状态时不加状态标签",
+			item: ArchivedIssue{State: "closed", Type: "pack-sync"},
+			want: []string{l.Marker, l.Type("pack-sync")},
+		},
+	}
+	for _, tt := range tests {
+		if got := importLabels(l, tt.item); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: importLabels = %q, 期望 %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -14,26 +14,69 @@ const (
 	LabelCursorToolset  = "cursortoolset"
 	LabelPending        = "pending"
 	LabelProcessing     = "processing"
+	LabelNeedsInfo      = "needs-info"
 	LabelProcessed      = "processed"
 	LabelRejected       = "rejected"
+	LabelDuplicate      = "duplicate"
 	LabelFeatureRequest = "feature-request"
 	LabelBugReport      = "bug-report"
 	LabelPackRegister   = "pack-register"
 	LabelPackSync       = "pack-sync"
+	LabelQuestion       = "question"
+	LabelCustom         = "custom"
 )
 
+// PayloadFileName Gist 中存放 Issue 包的文件名
+const PayloadFileName = "issue-payload.json"
+
 // IssueService Issue 服务
 type IssueService struct {
-	client *github.Client
+	client     *github.Client
+	labels     Labels
+	repoLabels map[string]Labels
+	registries *registryCache
+	progress   *progressReporter
+	warn       WarnFunc
+}
+
+// Config IssueService 配置
+type Config struct {
+	Token      string
+	APIHost    string            // 为空时使用 github.com
+	Labels     Labels            // 未配置的标签名使用默认值
+	RepoLabels map[string]Labels // 按仓库覆盖的标签配置 (owner/repo → Labels)
+	CacheDir   string            // API 响应缓存目录，为空时不使用缓存
 }
 
 // NewIssueService 创建 Issue 服务
 func NewIssueService(token string) *IssueService {
+	return NewIssueServiceWithConfig(Config{Token: token})
+}
+
+// NewIssueServiceWithConfig 按配置创建 Issue 服务
+func NewIssueServiceWithConfig(cfg Config) *IssueService {
+	client := github.NewClientWithBaseURL(cfg.Token, cfg.APIHost)
+	if cfg.CacheDir != "" {
+		client = client.WithCache(github.NewCache(cfg.CacheDir))
+	}
 	return &IssueService{
-		client: github.NewClient(token),
+		client:     client,
+		labels:     cfg.Labels,
+		repoLabels: cfg.RepoLabels,
+		registries: &registryCache{entries: make(map[string]registryEntry)},
 	}
 }
 
+// WithTracer 返回记录每次 GitHub API 调用的服务副本，用于诊断日志
+func (s *IssueService) WithTracer(fn func(github.RequestTrace)) *IssueService {
+	if fn == nil {
+		return s
+	}
+	cp := *s
+	cp.client = s.client.WithTracer(fn)
+	return &cp
+}
+
 // CreateIssueOptions 创建 Issue 的选项
 type CreateIssueOptions struct {
 	Repo        string
@@ -41,7 +84,10 @@ type CreateIssueOptions struct {
 	Title       string
 	Payload     interface{}
 	Attachments []models.Attachment
+	Metadata    *SourceMetadata // 写入 meta 的来源信息，nil 时不写入
 	DryRun      bool
+
+	SkipDuplicateCheck bool // 不检查与打开 Issue 的重复
 }
 
 // CreateIssueResult 创建 Issue 的结果
@@ -49,6 +95,23 @@ type CreateIssueResult struct {
 	IssueURL string
 	GistURL  string
 	IssueNum int
+	Preview  *CreatePreview // 仅 DryRun 时返回
+
+	// Duplicates 创建前发现的可能重复的打开 Issue，只作提示，不阻止创建
+	Duplicates []DuplicateCandidate
+
+	// Warnings 不影响创建的问题，如重复检查失败
+	Warnings []string
+}
+
+// CreatePreview Dry Run 时将要创建的内容
+type CreatePreview struct {
+	Repo    string
+	Type    models.IssueType
+	Title   string
+	Labels  []string
+	Summary string // 类型模板渲染的 Issue 摘要（可为空）
+	Package *models.IssuePackage
 }
 
 // Create 创建 Issue
@@ -58,12 +121,58 @@ func (s *IssueService) Create(opts CreateIssueOptions) (*CreateIssueResult, erro
 		return nil, err
 	}
 
+	// bug-report 未填写的环境信息使用探测到的值
+	if opts.Type == models.TypeBugReport {
+		opts.Payload = opts.Metadata.fillEnvironment(opts.Payload)
+	}
+
+	// 解析类型并按目标仓库注册的 schema 校验 payload
+	typeDef, registry, err := s.ResolveType(opts.Repo, opts.Type)
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	if registry == nil {
+		// 内置类型在注册表读取失败时仍可创建，但不会应用目标仓库的 schema、模板和标签
+		_, err := s.FetchTypeRegistry(opts.Repo)
+		warnings = append(warnings, fmt.Sprintf("%v，按内置定义创建 %s", err, opts.Type))
+	}
+	if typeDef != nil {
+		if err := typeDef.ValidatePayload(opts.Payload); err != nil {
+			return nil, err
+		}
+	}
+
+	s.progress.report("校验 payload")
+
+	// 检查目标仓库 manifest 的接收要求；Dry Run 时读取失败（如离线）只作为警告
+	manifest, err := s.FetchManifest(opts.Repo)
+	if err != nil {
+		if !opts.DryRun {
+			return nil, err
+		}
+		warnings = append(warnings, fmt.Sprintf("未检查目标仓库 manifest 的接收要求: %v", err))
+	}
+	if manifest != nil {
+		if err := manifest.Check(opts.Type, opts.Payload); err != nil {
+			return nil, err
+		}
+		if !manifest.AcceptsStorage("gist") {
+			return nil, fmt.Errorf("目标仓库不接受 gist 存储后端")
+		}
+	}
+	summary, err := renderBodyTemplate(typeDef, opts.Title, opts.Payload)
+	if err != nil {
+		return nil, err
+	}
+
 	// 构建 Issue 包
 	pkg, err := models.NewIssuePackage(opts.Type, opts.Repo, opts.Payload)
 	if err != nil {
 		return nil, fmt.Errorf("构建 Issue 包失败: %w", err)
 	}
 	pkg.Attachments = opts.Attachments
+	opts.Metadata.applyTo(pkg)
 
 	// 序列化为 JSON
 	pkgJSON, err := pkg.ToJSON()
@@ -71,23 +180,49 @@ func (s *IssueService) Create(opts CreateIssueOptions) (*CreateIssueResult, erro
 		return nil, fmt.Errorf("序列化 Issue 包失败: %w", err)
 	}
 
+	l := registry.applyTo(s.labelsFor(opts.Repo))
+	labels := []string{l.Marker, l.Pending, l.Type(string(opts.Type))}
+
+	// 重复检查失败（如网络错误）不影响创建，只作为警告返回
+	var duplicates []DuplicateCandidate
+	if !opts.SkipDuplicateCheck {
+		s.progress.report("检查重复")
+		var dupWarnings []string
+		duplicates, dupWarnings, err = s.FindDuplicates(DuplicateQuery{
+			Repo:    opts.Repo,
+			Type:    opts.Type,
+			Title:   opts.Title,
+			Payload: opts.Payload,
+		})
+		warnings = append(warnings, dupWarnings...)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("检查重复失败: %v", err))
+		}
+	}
+
 	if opts.DryRun {
-		fmt.Println("=== Dry Run 模式 ===")
-		fmt.Printf("目标仓库: %s/%s\n", owner, repo)
-		fmt.Printf("Issue 类型: %s\n", opts.Type)
-		fmt.Printf("标题: %s\n", opts.Title)
-		fmt.Println("\n=== Gist 内容 ===")
-		fmt.Println(pkgJSON)
-		return &CreateIssueResult{}, nil
+		return &CreateIssueResult{
+			Duplicates: duplicates,
+			Warnings:   warnings,
+			Preview: &CreatePreview{
+				Repo:    fmt.Sprintf("%s/%s", owner, repo),
+				Type:    opts.Type,
+				Title:   opts.Title,
+				Labels:  labels,
+				Summary: summary,
+				Package: pkg,
+			},
+		}, nil
 	}
 
 	// 创建 Gist
 	gistFiles := map[string]string{
-		"issue-payload.json": pkgJSON,
+		PayloadFileName: pkgJSON,
 	}
 	for _, att := range opts.Attachments {
 		gistFiles[att.Name] = att.Content
 	}
+	s.progress.report(fmt.Sprintf("上传 Gist (%d 个附件)", len(opts.Attachments)))
 
 	gist, err := s.client.CreateGist(
 		fmt.Sprintf("[%s] %s", opts.Type, opts.Title),
@@ -99,38 +234,40 @@ func (s *IssueService) Create(opts CreateIssueOptions) (*CreateIssueResult, erro
 	}
 
 	// 构建 Issue Body
-	body := buildIssueBody(opts.Type, opts.Title, gist.HTMLURL)
+	body := buildIssueBody(opts.Type, opts.Title, summary, gist.HTMLURL)
 
 	// 创建 Issue
-	labels := []string{LabelCursorToolset, LabelPending, string(opts.Type)}
+	s.progress.report("创建 Issue")
 	issue, err := s.client.CreateIssue(owner, repo, opts.Title, body, labels)
 	if err != nil {
 		return nil, fmt.Errorf("创建 Issue 失败: %w", err)
 	}
 
 	return &CreateIssueResult{
-		IssueURL: issue.HTMLURL,
-		GistURL:  gist.HTMLURL,
-		IssueNum: issue.Number,
+		IssueURL:   issue.HTMLURL,
+		GistURL:    gist.HTMLURL,
+		IssueNum:   issue.Number,
+		Duplicates: duplicates,
+		Warnings:   warnings,
 	}, nil
 }
 
 // ListOptions 列出 Issue 的选项
 type ListOptions struct {
 	Repo   string
-	Status string // pending, processing, processed, all
+	Status string // pending, processing, needs-info, processed, rejected, duplicate, all
 	Type   string
 	Limit  int
 }
 
 // IssueInfo Issue 信息
 type IssueInfo struct {
-	Number    int
-	Title     string
-	Type      string
-	Status    string
-	CreatedAt string
-	URL       string
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+	URL       string `json:"url"`
 }
 
 // List 列出 Issue
@@ -140,27 +277,59 @@ func (s *IssueService) List(opts ListOptions) ([]IssueInfo, error) {
 		return nil, err
 	}
 
-	// 构建标签过滤
-	labels := []string{LabelCursorToolset}
+	f := s.listFilter(opts)
+	issues, err := s.client.ListIssues(owner, repo, f.labels, f.state, opts.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toIssueInfos(opts.Repo, f.mapping, f.registryLoaded, issues), nil
+}
+
+// issueFilter 按状态和类型过滤 Issue 时使用的标签和状态
+type issueFilter struct {
+	mapping        Labels
+	registryLoaded bool
+	labels         []string
+	state          string // open, closed, all
+}
+
+// listFilter 构建标签过滤
+func (s *IssueService) listFilter(opts ListOptions) issueFilter {
+	l := s.labelsFor(opts.Repo)
+	registryLoaded := false
+	if opts.Type != "" && !models.IsBuiltinType(models.IssueType(opts.Type)) {
+		// 自定义类型的标签由目标仓库的注册表决定
+		registry, err := s.FetchTypeRegistry(opts.Repo)
+		if err != nil {
+			s.warnf("%v，按默认标签 %s 过滤类型 %s", err, l.Type(opts.Type), opts.Type)
+		}
+		l = registry.applyTo(l)
+		registryLoaded = true
+	}
+
+	labels := []string{l.Marker}
 	if opts.Status != "" && opts.Status != "all" {
-		labels = append(labels, opts.Status)
+		labels = append(labels, l.Status(opts.Status))
 	}
 	if opts.Type != "" {
-		labels = append(labels, opts.Type)
+		labels = append(labels, l.Type(opts.Type))
 	}
 
 	state := "open"
-	if opts.Status == "processed" || opts.Status == "rejected" {
+	if opts.Status == "processed" || opts.Status == "rejected" || opts.Status == "duplicate" {
 		state = "closed"
 	} else if opts.Status == "all" {
 		state = "all"
 	}
 
-	issues, err := s.client.ListIssues(owner, repo, labels, state, opts.Limit)
-	if err != nil {
-		return nil, err
-	}
+	return issueFilter{mapping: l, registryLoaded: registryLoaded, labels: labels, state: state}
+}
 
+// toIssueInfos 按标签映射解析 Issue 的类型和状态
+//
+// 遇到无法识别类型的 Issue 时，加载目标仓库的类型注册表后重新解析。
+func (s *IssueService) toIssueInfos(repoStr string, l Labels, registryLoaded bool, issues []github.Issue) []IssueInfo {
 	var result []IssueInfo
 	for _, issue := range issues {
 		info := IssueInfo{
@@ -171,25 +340,31 @@ func (s *IssueService) List(opts ListOptions) ([]IssueInfo, error) {
 		}
 
 		// 提取类型和状态
-		for _, label := range issue.Labels {
-			switch label.Name {
-			case LabelFeatureRequest, LabelBugReport, LabelPackRegister, LabelPackSync:
-				info.Type = label.Name
-			case LabelPending, LabelProcessing, LabelProcessed, LabelRejected:
-				info.Status = label.Name
+		info.Type, info.Status = l.Parse(issue.Labels)
+		if info.Type == "" && !registryLoaded {
+			// 可能是目标仓库注册的自定义类型，加载注册表后重新解析
+			registry, err := s.FetchTypeRegistry(repoStr)
+			if err != nil {
+				s.warnf("%v，无法识别自定义类型的 Issue 类型将显示为空", err)
 			}
+			l = registry.applyTo(l)
+			registryLoaded = true
+			info.Type, info.Status = l.Parse(issue.Labels)
 		}
 
 		result = append(result, info)
 	}
 
-	return result, nil
+	return result
 }
 
 // GetResult 获取 Issue 的结果
 type GetResult struct {
-	Issue   *github.Issue
-	Package *models.IssuePackage
+	Issue     *github.Issue
+	Package   *models.IssuePackage
+	Revision  string                // Package 对应的 Gist 版本
+	Revisions []github.GistRevision // Issue 包的全部版本，最新的在前
+	Comments  []ThreadComment       // 仅在调用方加载评论时设置
 }
 
 // Get 获取并解析 Issue
@@ -204,33 +379,46 @@ func (s *IssueService) Get(repoStr string, number int) (*GetResult, error) {
 		return nil, err
 	}
 
-	// 从 body 中提取 Gist URL
-	gistURL := extractGistURL(issue.Body)
-	if gistURL == "" {
-		return &GetResult{Issue: issue}, nil
+	result := &GetResult{Issue: issue}
+	result.Package, result.Revisions = s.issuePackage(issue)
+	if len(result.Revisions) > 0 {
+		result.Revision = result.Revisions[0].Version
 	}
+	return result, nil
+}
+
+// issuePackage 读取 Issue 关联 Gist 中的 Issue 包及其版本历史，没有可解析的包时返回 nil
+func (s *IssueService) issuePackage(issue *github.Issue) (*models.IssuePackage, []github.GistRevision) {
+	pkg, history, _ := s.readIssuePackage(issue)
+	return pkg, history
+}
 
-	// 提取 Gist ID
-	gistID := extractGistID(gistURL)
+// readIssuePackage 同 issuePackage，另外返回读取 Gist 的错误
+//
+// Issue 没有关联 Gist 或 Gist 中的包无法解析时返回 nil 且没有错误。
+func (s *IssueService) readIssuePackage(issue *github.Issue) (*models.IssuePackage, []github.GistRevision, error) {
+	// 从 body 中提取 Gist URL 和 ID
+	gistID := extractGistID(extractGistURL(issue.Body))
 	if gistID == "" {
-		return &GetResult{Issue: issue}, nil
+		return nil, nil, nil
 	}
 
 	// 获取 Gist 内容
 	gist, err := s.client.GetGist(gistID)
 	if err != nil {
-		return &GetResult{Issue: issue}, nil
+		return nil, nil, err
 	}
 
 	// 解析 payload
-	if file, ok := gist.Files["issue-payload.json"]; ok {
-		pkg, err := models.ParseIssuePackage(file.Content)
-		if err == nil {
-			return &GetResult{Issue: issue, Package: pkg}, nil
-		}
+	file, ok := gist.Files[PayloadFileName]
+	if !ok {
+		return nil, nil, nil
 	}
-
-	return &GetResult{Issue: issue}, nil
+	pkg, err := models.ParseIssuePackage(file.Content)
+	if err != nil {
+		return nil, nil, nil
+	}
+	return pkg, gist.History, nil
 }
 
 // UpdateStatus 更新 Issue 状态
@@ -240,6 +428,15 @@ func (s *IssueService) UpdateStatus(repoStr string, number int, status string, c
 		return err
 	}
 
+	body := statusComment(status, comment)
+	if status == "needs-info" {
+		// 问题是发起人回复的依据，stale 据此判断是否已回复
+		if strings.TrimSpace(comment) == "" {
+			return fmt.Errorf("needs-info 状态需要在评论中说明需要补充的信息")
+		}
+		body = needsInfoComment(comment)
+	}
+
 	// 获取当前 Issue
 	issue, err := s.client.GetIssue(owner, repo, number)
 	if err != nil {
@@ -247,14 +444,8 @@ func (s *IssueService) UpdateStatus(repoStr string, number int, status string, c
 	}
 
 	// 更新标签：移除旧状态，添加新状态
-	var newLabels []string
-	for _, label := range issue.Labels {
-		if label.Name != LabelPending && label.Name != LabelProcessing &&
-			label.Name != LabelProcessed && label.Name != LabelRejected {
-			newLabels = append(newLabels, label.Name)
-		}
-	}
-	newLabels = append(newLabels, status)
+	l := s.labelsFor(repoStr)
+	newLabels := l.WithStatus(issue.Labels, l.Status(status))
 
 	_, err = s.client.UpdateIssue(owner, repo, number, "", newLabels)
 	if err != nil {
@@ -263,7 +454,7 @@ func (s *IssueService) UpdateStatus(repoStr string, number int, status string, c
 
 	// 添加评论
 	if comment != "" {
-		err = s.client.AddComment(owner, repo, number, comment)
+		err = s.client.AddComment(owner, repo, number, body)
 		if err != nil {
 			return fmt.Errorf("添加评论失败: %w", err)
 		}
@@ -274,6 +465,27 @@ func (s *IssueService) UpdateStatus(repoStr string, number int, status string, c
 
 // Close 关闭 Issue
 func (s *IssueService) Close(repoStr string, number int, result string, comment string) error {
+	status := "processed"
+	if result == "rejected" {
+		status = "rejected"
+	}
+	return s.closeWithStatus(repoStr, number, status, comment)
+}
+
+// CloseDuplicate 以 duplicate 关闭 Issue，评论中以 "Duplicate of #N" 链接规范 Issue
+func (s *IssueService) CloseDuplicate(repoStr string, number, canonical int, comment string) error {
+	if canonical <= 0 || canonical == number {
+		return fmt.Errorf("无效的规范 Issue 编号: %d", canonical)
+	}
+	body := fmt.Sprintf("Duplicate of #%d", canonical)
+	if comment = strings.TrimSpace(comment); comment != "" {
+		body += "\n\n" + comment
+	}
+	return s.closeWithStatus(repoStr, number, "duplicate", body)
+}
+
+// closeWithStatus 关闭 Issue 并设置最终状态标签
+func (s *IssueService) closeWithStatus(repoStr string, number int, status string, comment string) error {
 	owner, repo, err := parseRepo(repoStr)
 	if err != nil {
 		return err
@@ -285,21 +497,9 @@ func (s *IssueService) Close(repoStr string, number int, result string, comment
 		return err
 	}
 
-	// 确定最终状态标签
-	statusLabel := LabelProcessed
-	if result == "rejected" {
-		statusLabel = LabelRejected
-	}
-
 	// 更新标签
-	var newLabels []string
-	for _, label := range issue.Labels {
-		if label.Name != LabelPending && label.Name != LabelProcessing &&
-			label.Name != LabelProcessed && label.Name != LabelRejected {
-			newLabels = append(newLabels, label.Name)
-		}
-	}
-	newLabels = append(newLabels, statusLabel)
+	l := s.labelsFor(repoStr)
+	newLabels := l.WithStatus(issue.Labels, l.Status(status))
 
 	// 关闭 Issue 并更新标签
 	_, err = s.client.UpdateIssue(owner, repo, number, "closed", newLabels)
@@ -309,7 +509,7 @@ func (s *IssueService) Close(repoStr string, number int, result string, comment
 
 	// 添加评论
 	if comment != "" {
-		err = s.client.AddComment(owner, repo, number, comment)
+		err = s.client.AddComment(owner, repo, number, statusComment(status, comment))
 		if err != nil {
 			return fmt.Errorf("添加评论失败: %w", err)
 		}
@@ -327,25 +527,29 @@ func parseRepo(repo string) (string, string, error) {
 	return parts[0], parts[1], nil
 }
 
-// buildIssueBody 构建 Issue Body
-func buildIssueBody(issueType models.IssueType, title string, gistURL string) string {
+// buildIssueBody 构建 Issue Body，summary 为类型模板渲染的摘要（可为空）
+func buildIssueBody(issueType models.IssueType, title string, summary string, gistURL string) string {
+	if summary != "" {
+		summary = "### Summary\n\n" + summary + "\n\n"
+	}
 	return fmt.Sprintf(`## %s: %s
 
 **Type:** %s
 **Created by:** github-issue-pack v0.1.0
 
-### Details
+%s### Details
 
 📦 [View full payload](%s)
 
 ---
 <sub>This issue was automatically created by [github-issue-pack](https://github.com/shichao402/github-issue-pack)</sub>
-`, issueType, title, issueType, gistURL)
+`, issueType, title, issueType, summary, gistURL)
 }
 
 // extractGistURL 从 Issue body 中提取 Gist URL
 func extractGistURL(body string) string {
-	re := regexp.MustCompile(`https://gist\.github\.com/[a-zA-Z0-9_-]+/[a-f0-9]+`)
+	// 同时支持 gist.github.com 和 GitHub Enterprise 的 https://HOST/gist/USER/ID
+	re := regexp.MustCompile(`https://(?:gist\.github\.com|[a-zA-Z0-9.-]+/gist)/[a-zA-Z0-9_-]+/[a-f0-9]+`)
 	match := re.FindString(body)
 	return match
 }
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+	"github.com/shichao402/github-issue-pack/internal/models"
+)
+
+// Statuses 全部状态名，按状态流转顺序排列
+var Statuses = []string{"pending", "processing", "needs-info", "processed", "rejected", "duplicate"}
+
+// Labels 标签体系：标记标签、状态标签和类型标签的名称映射，也是配置文件中 labels 的格式
+//
+// 未配置的标签使用默认名称；设置 Namespace 后默认名称改为
+// "<ns>"、"<ns>:status/<status>"、"<ns>:type/<type>"，如 "gip:status/pending"。
+type Labels struct {
+	Namespace  string            `yaml:"namespace,omitempty"`
+	Marker     string            `yaml:"marker,omitempty"` // 标记由本工具创建的 Issue
+	Pending    string            `yaml:"pending,omitempty"`
+	Processing string            `yaml:"processing,omitempty"`
+	NeedsInfo  string            `yaml:"needs_info,omitempty"`
+	Processed  string            `yaml:"processed,omitempty"`
+	Rejected   string            `yaml:"rejected,omitempty"`
+	Duplicate  string            `yaml:"duplicate,omitempty"`
+	Types      map[string]string `yaml:"types,omitempty"` // Issue 类型 → 标签名
+}
+
+// LabelSpec 标签定义，用于在仓库中创建标签
+type LabelSpec struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// DefaultLabels 返回默认标签名
+func DefaultLabels() Labels {
+	return Labels{}.withDefaults()
+}
+
+// Merge 用 other 中的非空字段覆盖当前标签配置
+func (l Labels) Merge(other Labels) Labels {
+	if other.Namespace != "" {
+		l.Namespace = other.Namespace
+	}
+	if other.Marker != "" {
+		l.Marker = other.Marker
+	}
+	if other.Pending != "" {
+		l.Pending = other.Pending
+	}
+	if other.Processing != "" {
+		l.Processing = other.Processing
+	}
+	if other.NeedsInfo != "" {
+		l.NeedsInfo = other.NeedsInfo
+	}
+	if other.Processed != "" {
+		l.Processed = other.Processed
+	}
+	if other.Rejected != "" {
+		l.Rejected = other.Rejected
+	}
+	if other.Duplicate != "" {
+		l.Duplicate = other.Duplicate
+	}
+	if len(other.Types) > 0 {
+		types := make(map[string]string, len(l.Types)+len(other.Types))
+		for t, name := range l.Types {
+			types[t] = name
+		}
+		for t, name := range other.Types {
+			types[t] = name
+		}
+		l.Types = types
+	}
+	return l
+}
+
+// withDefaults 用默认标签名填充未配置的字段
+func (l Labels) withDefaults() Labels {
+	if l.Marker == "" {
+		l.Marker = LabelCursorToolset
+		if l.Namespace != "" {
+			l.Marker = l.Namespace
+		}
+	}
+	if l.Pending == "" {
+		l.Pending = l.defaultStatus(LabelPending)
+	}
+	if l.Processing == "" {
+		l.Processing = l.defaultStatus(LabelProcessing)
+	}
+	if l.NeedsInfo == "" {
+		l.NeedsInfo = l.defaultStatus(LabelNeedsInfo)
+	}
+	if l.Processed == "" {
+		l.Processed = l.defaultStatus(LabelProcessed)
+	}
+	if l.Rejected == "" {
+		l.Rejected = l.defaultStatus(LabelRejected)
+	}
+	if l.Duplicate == "" {
+		l.Duplicate = l.defaultStatus(LabelDuplicate)
+	}
+	return l
+}
+
+// defaultStatus 状态标签的默认名称
+func (l Labels) defaultStatus(status string) string {
+	if l.Namespace == "" {
+		return status
+	}
+	return l.Namespace + ":status/" + status
+}
+
+// typePrefix 命名空间下类型标签的前缀
+func (l Labels) typePrefix() string {
+	return l.Namespace + ":type/"
+}
+
+// labelsFor 返回指定仓库生效的标签体系
+func (s *IssueService) labelsFor(repo string) Labels {
+	l := s.labels
+	if override, ok := s.repoLabels[repo]; ok {
+		l = l.Merge(override)
+	}
+	return l.withDefaults()
+}
+
+// Labels 返回指定仓库生效的标签体系
+func (s *IssueService) Labels(repo string) Labels {
+	return s.labelsFor(repo)
+}
+
+// Status 将状态名 (pending/processing/needs-info/processed/rejected/duplicate) 映射为标签名
+func (l Labels) Status(status string) string {
+	switch status {
+	case "pending":
+		return l.Pending
+	case "processing":
+		return l.Processing
+	case "needs-info":
+		return l.NeedsInfo
+	case "processed":
+		return l.Processed
+	case "rejected":
+		return l.Rejected
+	case "duplicate":
+		return l.Duplicate
+	}
+	return status
+}
+
+// StatusOf 将标签名映射回状态名，非状态标签返回空字符串
+func (l Labels) StatusOf(label string) string {
+	switch label {
+	case l.Pending:
+		return "pending"
+	case l.Processing:
+		return "processing"
+	case l.NeedsInfo:
+		return "needs-info"
+	case l.Processed:
+		return "processed"
+	case l.Rejected:
+		return "rejected"
+	case l.Duplicate:
+		return "duplicate"
+	}
+	return ""
+}
+
+// Type 将 Issue 类型映射为标签名
+func (l Labels) Type(issueType string) string {
+	if name, ok := l.Types[issueType]; ok {
+		return name
+	}
+	if l.Namespace != "" {
+		return l.typePrefix() + issueType
+	}
+	return issueType
+}
+
+// TypeOf 将标签名映射回 Issue 类型，非类型标签返回空字符串
+func (l Labels) TypeOf(label string) string {
+	for t, name := range l.Types {
+		if name == label {
+			return t
+		}
+	}
+	if l.Namespace != "" {
+		if strings.HasPrefix(label, l.typePrefix()) {
+			return strings.TrimPrefix(label, l.typePrefix())
+		}
+		return ""
+	}
+	for _, t := range models.BuiltinTypes {
+		if label == string(t) {
+			if _, remapped := l.Types[label]; remapped {
+				return ""
+			}
+			return label
+		}
+	}
+	return ""
+}
+
+// Parse 从 Issue 标签中提取类型和状态
+func (l Labels) Parse(labels []github.Label) (issueType, status string) {
+	for _, label := range labels {
+		if t := l.TypeOf(label.Name); t != "" {
+			issueType = t
+		}
+		if st := l.StatusOf(label.Name); st != "" {
+			status = st
+		}
+	}
+	return issueType, status
+}
+
+// WithStatus 移除现有的状态标签并添加新的状态标签，返回新的标签列表
+func (l Labels) WithStatus(labels []github.Label, statusLabel string) []string {
+	var result []string
+	for _, label := range labels {
+		if l.StatusOf(label.Name) == "" {
+			result = append(result, label.Name)
+		}
+	}
+	return append(result, statusLabel)
+}
+
+// Specs 返回标签体系中全部标签的定义，types 为需要创建类型标签的 Issue 类型
+func (l Labels) Specs(types []string) []LabelSpec {
+	specs := []LabelSpec{
+		{Name: l.Marker, Color: "7057ff", Description: "由 github-issue-pack 创建的 Issue"},
+		{Name: l.Pending, Color: "fbca04", Description: "待处理"},
+		{Name: l.Processing, Color: "0e8a16", Description: "处理中"},
+		{Name: l.NeedsInfo, Color: "d876e3", Description: "等待发起人补充信息"},
+		{Name: l.Processed, Color: "6f42c1", Description: "已处理完成"},
+		{Name: l.Rejected, Color: "d73a4a", Description: "已拒绝"},
+		{Name: l.Duplicate, Color: "cfd3d7", Description: "与其他 Issue 重复"},
+	}
+
+	typeColors := map[string]string{
+		string(models.TypeFeatureRequest): "a2eeef",
+		string(models.TypeBugReport):      "d73a4a",
+		string(models.TypePackRegister):   "0075ca",
+		string(models.TypePackSync):       "0075ca",
+	}
+
+	sorted := append([]string(nil), types...)
+	sort.Strings(sorted)
+	for _, t := range sorted {
+		color, ok := typeColors[t]
+		if !ok {
+			color = "c5def5"
+		}
+		specs = append(specs, LabelSpec{Name: l.Type(t), Color: color, Description: "Issue 类型: " + t})
+	}
+
+	return specs
+}
+
+// LabelSyncResult 标签同步结果
+type LabelSyncResult struct {
+	Created []LabelSpec `json:"created"`
+	Existed []LabelSpec `json:"existed"`
+}
+
+// SyncLabels 在仓库中创建标签体系中缺失的标签
+func (s *IssueService) SyncLabels(repoStr string, dryRun bool) (*LabelSyncResult, error) {
+	var types []string
+	for _, t := range models.BuiltinTypes {
+		types = append(types, string(t))
+	}
+	return s.syncLabels(repoStr, s.labelsFor(repoStr).Specs(types), dryRun)
+}
+
+// syncLabels 在仓库中创建 specs 中缺失的标签（按名称忽略大小写比较）
+func (s *IssueService) syncLabels(repoStr string, specs []LabelSpec, dryRun bool) (*LabelSyncResult, error) {
+	owner, repo, err := parseRepo(repoStr)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.client.ListLabels(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(existing))
+	for _, label := range existing {
+		names[strings.ToLower(label.Name)] = true
+	}
+
+	result := &LabelSyncResult{}
+	for _, spec := range specs {
+		if names[strings.ToLower(spec.Name)] {
+			result.Existed = append(result.Existed, spec)
+			continue
+		}
+		if !dryRun {
+			if _, err := s.client.CreateLabel(owner, repo, spec.Name, spec.Color, spec.Description); err != nil {
+				return result, err
+			}
+		}
+		names[strings.ToLower(spec.Name)] = true
+		result.Created = append(result.Created, spec)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+)
+
+func TestLabelsTypeOf(t *testing.T) {
+	remapped := Labels{Types: map[string]string{"bug-report": "bug"}}.withDefaults()
+	namespaced := Labels{Namespace: "gip"}.withDefaults()
+
+	tests := []struct {
+		name   string
+		labels Labels
+		label  string
+		want   string
+	}{
+		{"内置类型", DefaultLabels(), "bug-report", "bug-report"},
+		{"状态标签", DefaultLabels(), "pending", ""},
+		{"未注册类型", DefaultLabels(), "my-type", ""},
+		{"重命名后的标签", remapped, "bug", "bug-report"},
+		{"重命名后原名不再是类型", remapped, "bug-report", ""},
+		{"其他内置类型不受影响", remapped, "feature-request", "feature-request"},
+		{"命名空间类型", namespaced, "gip:type/bug-report", "bug-report"},
+		{"命名空间自定义类型", namespaced, "gip:type/my-type", "my-type"},
+		{"命名空间外的类型名", namespaced, "bug-report", ""},
+		{"命名空间状态标签", namespaced, "gip:status/pending", ""},
+	}
+	for _, tt := range tests {
+		if got := tt.labels.TypeOf(tt.label); got != tt.want {
+			t.Errorf("%s: TypeOf(%q) = %q, 期望 %q", tt.name, tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestLabelsParse(t *testing.T) {
+	namespaced := Labels{Namespace: "gip", NeedsInfo: "waiting"}.withDefaults()
+
+	tests := []struct {
+		name       string
+		labels     Labels
+		names      []string
+		wantType   string
+		wantStatus string
+	}{
+		{"默认标签", DefaultLabels(), []string{"cursortoolset", "bug-report", "processing"}, "bug-report", "processing"},
+		{"没有类型和状态", DefaultLabels(), []string{"cursortoolset", "help wanted"}, "", ""},
+		{"只有状态", DefaultLabels(), []string{"duplicate"}, "", "duplicate"},
+		{"命名空间", namespaced, []string{"gip", "gip:type/pack-sync", "gip:status/processed"}, "pack-sync", "processed"},
+		{"自定义状态标签", namespaced, []string{"gip:type/bug-report", "waiting"}, "bug-report", "needs-info"},
+		{"命名空间外的标签被忽略", namespaced, []string{"bug-report", "pending"}, "", ""},
+	}
+	for _, tt := range tests {
+		var labels []github.Label
+		for _, name := range tt.names {
+			labels = append(labels, github.Label{Name: name})
+		}
+		gotType, gotStatus := tt.labels.Parse(labels)
+		if gotType != tt.wantType || gotStatus != tt.wantStatus {
+			t.Errorf("%s: Parse(%v) = (%q, %q), 期望 (%q, %q)",
+				tt.name, tt.names, gotType, gotStatus, tt.wantType, tt.wantStatus)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+	"github.com/shichao402/github-issue-pack/internal/models"
+)
+
+// ManifestPath 目标仓库中 manifest 的路径
+const ManifestPath = ".github/issue-pack/manifest.json"
+
+// FetchManifest 读取目标仓库的 manifest，仓库未发布 manifest 时返回 nil
+func (s *IssueService) FetchManifest(repoStr string) (*models.Manifest, error) {
+	owner, repo, err := parseRepo(repoStr)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.client.GetFileContent(owner, repo, ManifestPath)
+	if err != nil {
+		if github.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取 manifest 失败: %w", err)
+	}
+
+	return models.ParseManifest(data)
+}
+
+// DiscoverResult 目标仓库的接收能力
+type DiscoverResult struct {
+	Repo         string           `json:"repo"`
+	AcceptsPacks bool             `json:"accepts_packs"`
+	Manifest     *models.Manifest `json:"manifest,omitempty"`
+	Types        []string         `json:"types"`
+	InvalidTypes []string         `json:"invalid_types,omitempty"`
+}
+
+// Discover 读取目标仓库的 manifest 和类型注册表，汇总其接收能力
+//
+// 仓库发布了 manifest 或类型注册表时视为接受 Issue 包。
+func (s *IssueService) Discover(repoStr string) (*DiscoverResult, error) {
+	manifest, err := s.FetchManifest(repoStr)
+	if err != nil {
+		return nil, err
+	}
+	registry, err := s.FetchTypeRegistry(repoStr)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DiscoverResult{
+		Repo:         repoStr,
+		AcceptsPacks: manifest != nil || len(registry.Types) > 0,
+		Manifest:     manifest,
+	}
+
+	// 可用类型：内置类型 + 注册类型，再按 manifest 过滤
+	seen := make(map[string]bool)
+	candidates := registry.Names()
+	for _, t := range models.BuiltinTypes {
+		candidates = append(candidates, string(t))
+	}
+	for _, t := range candidates {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if manifest == nil || manifest.Accepts(models.IssueType(t)) {
+			result.Types = append(result.Types, t)
+		}
+	}
+	result.InvalidTypes = registry.InvalidNames()
+
+	return result, nil
+}
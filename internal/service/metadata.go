@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/models"
+)
+
+// SourceMetadata 从本地环境探测的来源信息，写入 Issue 包的 meta 和 payload.environment
+type SourceMetadata struct {
+	SourceProject        string `json:"source_project,omitempty"`
+	SourceCommit         string `json:"source_commit,omitempty"`
+	SourceBranch         string `json:"source_branch,omitempty"`
+	Platform             string `json:"os,omitempty"`
+	CursorToolsetVersion string `json:"cursortoolset_version,omitempty"`
+	Pack                 string `json:"pack,omitempty"`
+	PackVersion          string `json:"pack_version,omitempty"`
+}
+
+// MetadataKeys 可覆盖的元数据字段名
+var MetadataKeys = []string{"source_project", "source_commit", "source_branch", "os", "cursortoolset_version", "pack", "pack_version"}
+
+func (m *SourceMetadata) field(key string) *string {
+	switch key {
+	case "source_project":
+		return &m.SourceProject
+	case "source_commit":
+		return &m.SourceCommit
+	case "source_branch":
+		return &m.SourceBranch
+	case "os":
+		return &m.Platform
+	case "cursortoolset_version":
+		return &m.CursorToolsetVersion
+	case "pack":
+		return &m.Pack
+	case "pack_version":
+		return &m.PackVersion
+	}
+	return nil
+}
+
+// Set 覆盖单个字段，value 为空时不写入该字段
+func (m *SourceMetadata) Set(key, value string) error {
+	f := m.field(key)
+	if f == nil {
+		return fmt.Errorf("未知的元数据字段: %s (可用: %s)", key, strings.Join(MetadataKeys, ", "))
+	}
+	*f = value
+	return nil
+}
+
+// Get 读取单个字段
+func (m *SourceMetadata) Get(key string) string {
+	if f := m.field(key); f != nil {
+		return *f
+	}
+	return ""
+}
+
+// DetectMetadata 探测 dir 所在的 git 仓库、当前系统和 CursorToolset 版本
+//
+// packDir 非空时读取其中的 package.json / version.json 作为包名和版本。
+// 探测失败的字段留空，不会返回错误。
+func DetectMetadata(dir, packDir string) *SourceMetadata {
+	m := &SourceMetadata{
+		Platform: runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if remote := gitOutput(dir, "remote", "get-url", "origin"); remote != "" {
+		m.SourceProject = normalizeRemote(remote)
+	}
+	m.SourceCommit = gitOutput(dir, "rev-parse", "HEAD")
+	// 分离 HEAD 时 --abbrev-ref 返回 "HEAD"
+	if branch := gitOutput(dir, "rev-parse", "--abbrev-ref", "HEAD"); branch != "HEAD" {
+		m.SourceBranch = branch
+	}
+
+	m.CursorToolsetVersion = detectCursorToolsetVersion()
+
+	if packDir != "" {
+		m.Pack, m.PackVersion = ReadPackVersion(packDir)
+	}
+	return m
+}
+
+// gitOutput 执行 git 命令并返回去除空白的输出，失败时返回空字符串
+func gitOutput(dir string, args ...string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// normalizeRemote 将 git remote 地址规范化：GitHub 仓库返回 owner/repo，
+// 其他地址返回 host/path；地址中的用户名和凭据不会保留
+func normalizeRemote(remote string) string {
+	var host, path string
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if at := strings.Index(remote, "@"); at >= 0 && strings.Contains(remote[at:], ":") {
+		// scp 风格: git@github.com:owner/repo.git
+		rest := remote[at+1:]
+		colon := strings.Index(rest, ":")
+		host, path = rest[:colon], rest[colon+1:]
+	} else {
+		// 本地路径等无法识别的地址不作为来源项目
+		return ""
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "github.com" {
+		return path
+	}
+	return host + "/" + path
+}
+
+var versionPattern = regexp.MustCompile(`\d+\.\d+\.\d+[0-9A-Za-z.+-]*`)
+
+// detectCursorToolsetVersion 读取 CURSORTOOLSET_VERSION，未设置时执行 cursortoolset --version
+func detectCursorToolsetVersion() string {
+	if v := os.Getenv("CURSORTOOLSET_VERSION"); v != "" {
+		return v
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "cursortoolset", "--version").Output()
+	if err != nil {
+		return ""
+	}
+	return versionPattern.FindString(string(out))
+}
+
+// ReadPackVersion 从 package.json 读取包名和版本，没有时读取 version.json
+//
+// version.json 的格式为 {"<包名>": {"version": "..."}}。
+func ReadPackVersion(dir string) (name, version string) {
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var pkg struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		}
+		if json.Unmarshal(data, &pkg) == nil && pkg.Version != "" {
+			return pkg.Name, pkg.Version
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "version.json"))
+	if err != nil {
+		return "", ""
+	}
+	var versions map[string]struct {
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(data, &versions) != nil || len(versions) == 0 {
+		return "", ""
+	}
+	names := make([]string, 0, len(versions))
+	for n := range versions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names[0], versions[names[0]].Version
+}
+
+// applyTo 写入 Issue 包的 meta
+func (m *SourceMetadata) applyTo(pkg *models.IssuePackage) {
+	if m == nil {
+		return
+	}
+	pkg.Meta.SourceProject = m.SourceProject
+	pkg.Meta.SourceCommit = m.SourceCommit
+	pkg.Meta.SourceBranch = m.SourceBranch
+	pkg.Meta.Platform = m.Platform
+	pkg.Meta.CursorToolsetVersion = m.CursorToolsetVersion
+	pkg.Meta.SourcePack = m.Pack
+	pkg.Meta.SourcePackVersion = m.PackVersion
+}
+
+// fillEnvironment 补全 bug-report payload 中未填写的 environment 字段
+//
+// payload 无法转换为 JSON 对象时原样返回。
+func (m *SourceMetadata) fillEnvironment(payload interface{}) interface{} {
+	if m == nil || payload == nil {
+		return payload
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return payload
+	}
+	var obj map[string]interface{}
+	if json.Unmarshal(data, &obj) != nil || obj == nil {
+		return payload
+	}
+
+	env, _ := obj["environment"].(map[string]interface{})
+	if env == nil {
+		env = map[string]interface{}{}
+	}
+	for _, key := range []string{"os", "cursortoolset_version", "pack_version"} {
+		if s, _ := env[key].(string); s == "" {
+			if v := m.Get(key); v != "" {
+				env[key] = v
+			}
+		}
+	}
+	if len(env) > 0 {
+		obj["environment"] = env
+	}
+	return obj
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ProgressFunc 进度回调，progress 单调递增，total 未知时为 0
+type ProgressFunc func(progress, total float64, message string)
+
+// progressReporter 在一次调用的多个步骤间累计进度
+type progressReporter struct {
+	mu   sync.Mutex
+	fn   ProgressFunc
+	done float64
+}
+
+func (p *progressReporter) report(message string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.fn(p.done, 0, message)
+}
+
+// WarnFunc 警告回调，用于不影响结果但调用方应当知道的问题（如类型注册表读取失败）
+type WarnFunc func(message string)
+
+// WithWarnings 返回通过 fn 报告警告的服务副本
+func (s *IssueService) WithWarnings(fn WarnFunc) *IssueService {
+	cp := *s
+	cp.warn = fn
+	return &cp
+}
+
+func (s *IssueService) warnf(format string, args ...interface{}) {
+	if s.warn != nil {
+		s.warn(fmt.Sprintf(format, args...))
+	}
+}
+
+// WithContext 返回使用指定 context 的服务副本，context 取消时中断进行中的 GitHub 请求
+func (s *IssueService) WithContext(ctx context.Context) *IssueService {
+	cp := *s
+	cp.client = s.client.WithContext(ctx)
+	return &cp
+}
+
+// WithProgress 返回报告进度的服务副本，用于多页列表和附件上传等耗时操作
+func (s *IssueService) WithProgress(fn ProgressFunc) *IssueService {
+	if fn == nil {
+		return s
+	}
+	p := &progressReporter{fn: fn}
+	cp := *s
+	cp.progress = p
+	cp.client = s.client.WithPageHook(func(fetched int) {
+		p.report(fmt.Sprintf("已获取 %d 条记录", fetched))
+	})
+	return &cp
+}
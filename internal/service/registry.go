@@ -0,0 +1,213 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+	"github.com/shichao402/github-issue-pack/internal/models"
+)
+
+// TypeRegistryPath 目标仓库中类型注册表的目录
+const TypeRegistryPath = ".github/issue-pack/types"
+
+// TypeRegistry 目标仓库注册的 Issue 类型
+type TypeRegistry struct {
+	Types   map[string]*models.TypeDefinition
+	Invalid map[string]error // 无法解析的类型定义文件，按类型名（文件名）索引
+}
+
+// Names 返回排序后的已注册类型名
+func (r *TypeRegistry) Names() []string {
+	names := make([]string, 0, len(r.Types))
+	for name := range r.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// InvalidNames 返回定义无效的类型名（排序）
+func (r *TypeRegistry) InvalidNames() []string {
+	names := make([]string, 0, len(r.Invalid))
+	for name := range r.Invalid {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyTo 将注册类型的标签合并到标签体系中，配置文件中的映射优先
+func (r *TypeRegistry) applyTo(l Labels) Labels {
+	if r == nil || len(r.Types) == 0 {
+		return l
+	}
+
+	types := make(map[string]string, len(l.Types)+len(r.Types))
+	for name, def := range r.Types {
+		if def.Label != "" {
+			types[name] = def.Label
+		}
+	}
+	for t, name := range l.Types {
+		types[t] = name
+	}
+	l.Types = types
+
+	// 未指定标签的注册类型在无命名空间时使用类型名作为标签
+	if l.Namespace == "" {
+		for name := range r.Types {
+			if _, ok := l.Types[name]; !ok {
+				l.Types[name] = name
+			}
+		}
+	}
+	return l
+}
+
+// registryCacheTTL 类型注册表在同一服务实例内的缓存时间
+const registryCacheTTL = 5 * time.Minute
+
+// registryCache 同一服务实例（及其副本）内复用已读取的类型注册表，读取失败的结果同样缓存
+type registryCache struct {
+	mu      sync.Mutex
+	entries map[string]registryEntry
+}
+
+type registryEntry struct {
+	registry *TypeRegistry
+	err      error
+	at       time.Time
+}
+
+// FetchTypeRegistry 读取目标仓库的类型注册表，仓库未发布注册表时返回空注册表
+//
+// 结果在服务实例内缓存 registryCacheTTL，一次命令中多处使用注册表时只读取一次。
+func (s *IssueService) FetchTypeRegistry(repoStr string) (*TypeRegistry, error) {
+	if s.registries == nil {
+		return s.fetchTypeRegistry(repoStr)
+	}
+	s.registries.mu.Lock()
+	defer s.registries.mu.Unlock()
+	if e, ok := s.registries.entries[repoStr]; ok && time.Since(e.at) < registryCacheTTL {
+		return e.registry, e.err
+	}
+	registry, err := s.fetchTypeRegistry(repoStr)
+	s.registries.entries[repoStr] = registryEntry{registry: registry, err: err, at: time.Now()}
+	return registry, err
+}
+
+func (s *IssueService) fetchTypeRegistry(repoStr string) (*TypeRegistry, error) {
+	owner, repo, err := parseRepo(repoStr)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := &TypeRegistry{
+		Types:   make(map[string]*models.TypeDefinition),
+		Invalid: make(map[string]error),
+	}
+
+	entries, err := s.client.ListDirectory(owner, repo, TypeRegistryPath)
+	if err != nil {
+		if github.IsNotFound(err) {
+			return registry, nil
+		}
+		return nil, fmt.Errorf("读取类型注册表失败: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.Type != "file" || !strings.HasSuffix(entry.Name, ".json") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name, ".json")
+
+		data, err := s.client.GetFileContent(owner, repo, path.Join(TypeRegistryPath, entry.Name))
+		if err != nil {
+			registry.Invalid[name] = err
+			continue
+		}
+		def, err := models.ParseTypeDefinition(data, name)
+		if err != nil {
+			registry.Invalid[name] = err
+			continue
+		}
+		registry.Types[def.Name] = def
+	}
+
+	return registry, nil
+}
+
+// ResolveType 在目标仓库中解析 Issue 类型
+//
+// 注册表中的定义优先（可为内置类型补充 schema）；内置类型未注册时返回 nil 定义；
+// 既非内置也未注册的类型返回错误。
+func (s *IssueService) ResolveType(repoStr string, issueType models.IssueType) (*models.TypeDefinition, *TypeRegistry, error) {
+	registry, err := s.FetchTypeRegistry(repoStr)
+	if err != nil {
+		if models.IsBuiltinType(issueType) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	name := string(issueType)
+	if def, ok := registry.Types[name]; ok {
+		return def, registry, nil
+	}
+	if err, ok := registry.Invalid[name]; ok {
+		return nil, registry, fmt.Errorf("目标仓库中 %s 类型的定义无效: %w", name, err)
+	}
+	if models.IsBuiltinType(issueType) {
+		return nil, registry, nil
+	}
+
+	available := registry.Names()
+	for _, t := range models.BuiltinTypes {
+		available = append(available, string(t))
+	}
+	return nil, registry, fmt.Errorf("无效的 Issue 类型: %s (目标仓库 %s 可用类型: %s)",
+		name, repoStr, strings.Join(available, ", "))
+}
+
+// bodyTemplateData Issue body 模板的数据
+type bodyTemplateData struct {
+	Type    string
+	Title   string
+	Payload interface{}
+}
+
+// renderBodyTemplate 用类型定义的 body 模板渲染 Issue 摘要
+func renderBodyTemplate(def *models.TypeDefinition, title string, payload interface{}) (string, error) {
+	if def == nil || def.BodyTemplate == "" {
+		return "", nil
+	}
+
+	tmpl, err := template.New(def.Name).Option("missingkey=zero").Parse(def.BodyTemplate)
+	if err != nil {
+		return "", fmt.Errorf("解析 %s 类型的 body 模板失败: %w", def.Name, err)
+	}
+
+	// 统一转换为通用 JSON 结构，模板中使用 JSON 字段名访问
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("序列化 payload 失败: %w", err)
+	}
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return "", fmt.Errorf("解析 payload 失败: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, bodyTemplateData{Type: def.Name, Title: title, Payload: data}); err != nil {
+		return "", fmt.Errorf("渲染 %s 类型的 body 模板失败: %w", def.Name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
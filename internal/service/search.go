@@ -0,0 +1,52 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SearchOptions 搜索 Issue 的选项，Query 为 GitHub 搜索语法的关键字和限定符
+type SearchOptions struct {
+	ListOptions
+	Query string
+}
+
+// Search 在标准化 Issue 中搜索
+//
+// 在 Query 之外自动限定仓库、标记标签，并按 Status/Type 追加标签和开闭状态限定。
+func (s *IssueService) Search(opts SearchOptions) ([]IssueInfo, error) {
+	infos, _, err := s.search(opts)
+	return infos, err
+}
+
+// search 同 Search，同时返回匹配总数
+func (s *IssueService) search(opts SearchOptions) ([]IssueInfo, int, error) {
+	if _, _, err := parseRepo(opts.Repo); err != nil {
+		return nil, 0, err
+	}
+
+	f := s.listFilter(opts.ListOptions)
+	query := searchQualifiers(opts.Repo, f)
+	if q := strings.TrimSpace(opts.Query); q != "" {
+		query += " " + q
+	}
+
+	issues, total, err := s.client.SearchIssuesWithTotal(query, opts.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.toIssueInfos(opts.Repo, f.mapping, f.registryLoaded, issues), total, nil
+}
+
+// searchQualifiers 限定仓库、标签和开闭状态的搜索限定符
+func searchQualifiers(repo string, f issueFilter) string {
+	qualifiers := []string{"repo:" + repo, "is:issue"}
+	for _, label := range f.labels {
+		qualifiers = append(qualifiers, fmt.Sprintf("label:%q", label))
+	}
+	if f.state != "all" {
+		qualifiers = append(qualifiers, "is:"+f.state)
+	}
+	return strings.Join(qualifiers, " ")
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+// StaleAction needs-info Issue 的处理动作
+type StaleAction string
+
+const (
+	StaleResumed  StaleAction = "resumed"  // 发起人已回复，恢复为 pending
+	StaleReminded StaleAction = "reminded" // 发表催促评论
+	StaleClosed   StaleAction = "closed"   // 长时间未回复，关闭
+	StaleWaiting  StaleAction = "waiting"  // 未到阈值，继续等待
+	StaleSkipped  StaleAction = "skipped"  // 无法判断（如找不到问题评论）
+)
+
+// StaleOptions 处理 needs-info Issue 的选项
+type StaleOptions struct {
+	Repo        string
+	RemindAfter time.Duration // 提问（或上次催促）后超过该时长未回复则催促，0 表示不催促
+	CloseAfter  time.Duration // 提问后超过该时长未回复则关闭，0 表示不关闭
+	DryRun      bool
+}
+
+// StaleItem 单个 Issue 的处理结果
+type StaleItem struct {
+	Number  int         `json:"number"`
+	Title   string      `json:"title"`
+	Author  string      `json:"author"`
+	AskedAt string      `json:"asked_at,omitempty"`
+	Action  StaleAction `json:"action"`
+	Reason  string      `json:"reason"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// StaleReport 处理报告
+type StaleReport struct {
+	DryRun bool        `json:"dry_run"`
+	Items  []StaleItem `json:"items"`
+}
+
+// Stale 检查处于 needs-info 状态的 Issue
+//
+// 发起人在问题之后发表了评论或更新了 Issue 包时恢复为 pending；
+// 否则按阈值催促发起人，超过 CloseAfter 后以 rejected 关闭。
+func (s *IssueService) Stale(opts StaleOptions) (*StaleReport, error) {
+	owner, repo, err := parseRepo(opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	l := s.labelsFor(opts.Repo)
+	issues, err := s.client.ListIssues(owner, repo, []string{l.Marker, l.NeedsInfo}, "open", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &StaleReport{DryRun: opts.DryRun, Items: []StaleItem{}}
+	now := time.Now()
+	for _, issue := range issues {
+		item := StaleItem{Number: issue.Number, Title: issue.Title, Author: issue.User.Login}
+		if err := s.checkStale(opts, &item, issue.Body, now); err != nil {
+			item.Error = err.Error()
+		}
+		report.Items = append(report.Items, item)
+	}
+	return report, nil
+}
+
+// checkStale 判断单个 Issue 的处理动作，非 dry-run 时执行
+func (s *IssueService) checkStale(opts StaleOptions, item *StaleItem, issueBody string, now time.Time) error {
+	thread, err := s.Comments(opts.Repo, item.Number)
+	if err != nil {
+		item.Action, item.Reason = StaleSkipped, "获取评论失败"
+		return err
+	}
+
+	// 最近一次 needs-info 提问
+	asked := -1
+	for i, c := range thread {
+		if c.Status == "needs-info" {
+			asked = i
+		}
+	}
+	if asked < 0 {
+		item.Action, item.Reason = StaleSkipped, "未找到 needs-info 问题评论"
+		return nil
+	}
+	item.AskedAt = thread[asked].CreatedAt
+	askedAt, err := time.Parse(time.RFC3339, item.AskedAt)
+	if err != nil {
+		item.Action, item.Reason = StaleSkipped, "无法解析提问时间"
+		return nil
+	}
+
+	// 发起人的回复或 Issue 包更新
+	lastPing := askedAt
+	for _, c := range thread[asked+1:] {
+		if c.Reminder {
+			if t, err := time.Parse(time.RFC3339, c.CreatedAt); err == nil {
+				lastPing = t
+			}
+			continue
+		}
+		if !c.IsGenerated() && c.Author == item.Author {
+			item.Action, item.Reason = StaleResumed, fmt.Sprintf("发起人已于 %s 回复", c.CreatedAt)
+			return s.resume(opts, item)
+		}
+	}
+	if updatedAt := s.packageUpdatedAt(issueBody); updatedAt.After(askedAt) {
+		item.Action, item.Reason = StaleResumed, fmt.Sprintf("Issue 包已于 %s 更新", updatedAt.UTC().Format(time.RFC3339))
+		return s.resume(opts, item)
+	}
+
+	waited := now.Sub(askedAt)
+	switch {
+	case opts.CloseAfter > 0 && waited >= opts.CloseAfter:
+		item.Action, item.Reason = StaleClosed, fmt.Sprintf("超过 %s未回复", formatDays(opts.CloseAfter))
+		if opts.DryRun {
+			return nil
+		}
+		return s.Close(opts.Repo, item.Number, "rejected",
+			fmt.Sprintf("提问后超过 %s未收到回复，自动关闭。如仍需处理，请补充信息后重新提交。", formatDays(opts.CloseAfter)))
+	case opts.RemindAfter > 0 && now.Sub(lastPing) >= opts.RemindAfter:
+		item.Action, item.Reason = StaleReminded, fmt.Sprintf("已等待 %s", formatDays(waited))
+		if opts.DryRun {
+			return nil
+		}
+		owner, repo, _ := parseRepo(opts.Repo)
+		body := fmt.Sprintf("%s\n@%s 提醒：此 Issue 仍在等待你补充信息（见上方问题）。", reminderMarker, item.Author)
+		if opts.CloseAfter > 0 {
+			body += fmt.Sprintf("超过 %s未回复将自动关闭。", formatDays(opts.CloseAfter))
+		}
+		return s.client.AddComment(owner, repo, item.Number, body)
+	default:
+		item.Action, item.Reason = StaleWaiting, fmt.Sprintf("已等待 %s", formatDays(waited))
+		return nil
+	}
+}
+
+// resume 将 Issue 恢复为 pending
+func (s *IssueService) resume(opts StaleOptions, item *StaleItem) error {
+	if opts.DryRun {
+		return nil
+	}
+	return s.UpdateStatus(opts.Repo, item.Number, "pending", item.Reason+"，已恢复为待处理。")
+}
+
+// packageUpdatedAt 返回 Issue 关联 Gist 的更新时间，无法获取时返回零值
+func (s *IssueService) packageUpdatedAt(issueBody string) time.Time {
+	gistID := extractGistID(extractGistURL(issueBody))
+	if gistID == "" {
+		return time.Time{}
+	}
+	gist, err := s.client.GetGist(gistID)
+	if err != nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339, gist.UpdatedAt)
+	return t
+}
+
+// formatDays 以天为单位显示时长，不足一天时按小时显示
+func formatDays(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%d 天", int(d/(24*time.Hour)))
+	}
+	return fmt.Sprintf("%d 小时", int(d/time.Hour))
+}
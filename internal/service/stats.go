@@ -0,0 +1,381 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+)
+
+// StatsOptions 统计选项
+type StatsOptions struct {
+	Repo          string
+	Since         time.Duration // 统计该时长内创建的 Issue，0 表示全部
+	Type          string
+	MaxPendingAge time.Duration // pending 超过该时长视为超出 SLA，0 时使用 manifest 的 sla.first_response
+}
+
+// DurationStats 一组时长的统计，单位为小时
+type DurationStats struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean_hours"`
+	Median float64 `json:"median_hours"`
+	P90    float64 `json:"p90_hours"`
+	Max    float64 `json:"max_hours"`
+}
+
+// PendingItem 待处理的 Issue
+type PendingItem struct {
+	Number   int     `json:"number"`
+	Title    string  `json:"title"`
+	Type     string  `json:"type"`
+	URL      string  `json:"url"`
+	Since    string  `json:"pending_since"` // 最近一次进入 pending 的时间
+	AgeHours float64 `json:"age_hours"`
+}
+
+// SLAReport pending 时效检查结果
+type SLAReport struct {
+	MaxPendingAge string        `json:"max_pending_age"`
+	Source        string        `json:"source"` // flag 或 manifest
+	Breaches      []PendingItem `json:"breaches"`
+}
+
+// StatsReport 统计报告
+//
+// 计数、响应和处理时长只统计时间窗口内创建的 Issue；Pending、OldestPending 和 SLA
+// 统计当前全部待处理的 Issue。
+type StatsReport struct {
+	Repo          string                    `json:"repo"`
+	Since         string                    `json:"since,omitempty"`
+	GeneratedAt   string                    `json:"generated_at"`
+	Total         int                       `json:"total"`
+	Counts        map[string]map[string]int `json:"counts"` // 类型 → 状态 → 数量
+	FirstResponse DurationStats             `json:"first_response"`
+	Unanswered    int                       `json:"unanswered"` // 尚未得到响应的 Issue
+	Processing    DurationStats             `json:"processing"` // 已结束的 processing 阶段
+	InProcessing  int                       `json:"in_processing"`
+	Processed     int                       `json:"processed"`
+	Rejected      int                       `json:"rejected"`
+	Duplicate     int                       `json:"duplicate"`
+	RejectionRate float64                   `json:"rejection_rate"` // rejected / (processed + rejected)
+	Pending       int                       `json:"pending"`
+	OldestPending *PendingItem              `json:"oldest_pending,omitempty"`
+	SLA           *SLAReport                `json:"sla,omitempty"`
+}
+
+// statusChange 一次状态变化，status 为 closed 表示 Issue 被关闭
+type statusChange struct {
+	at     time.Time
+	status string
+}
+
+// Stats 根据 Issue 的事件时间线和评论统计处理情况
+//
+// 首次响应为以下最早发生者：非发起人的评论、状态从 pending 变为其他状态、Issue 关闭。
+// processing 时长为每次进入 processing 到下一次状态变化或关闭之间的时间。
+func (s *IssueService) Stats(opts StatsOptions) (*StatsReport, error) {
+	owner, repo, err := parseRepo(opts.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &StatsReport{
+		Repo:        opts.Repo,
+		GeneratedAt: now.UTC().Format(time.RFC3339),
+		Counts:      map[string]map[string]int{},
+	}
+
+	f := s.listFilter(ListOptions{Repo: opts.Repo, Status: "all", Type: opts.Type})
+	var since time.Time
+	var issues []github.Issue
+	if opts.Since > 0 {
+		since = now.Add(-opts.Since)
+		report.Since = since.UTC().Format(time.RFC3339)
+		// 在 since 之后创建的 Issue 一定在 since 之后更新过
+		issues, _, _, err = s.client.ListIssuesSince(owner, repo, f.labels, f.state, report.Since, "")
+	} else {
+		issues, err = s.client.ListIssues(owner, repo, f.labels, f.state, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var firstResponse, processing []time.Duration
+	l := f.mapping
+	for _, issue := range issues {
+		created, err := time.Parse(time.RFC3339, issue.CreatedAt)
+		if err != nil || created.Before(since) {
+			continue
+		}
+		s.progress.report(fmt.Sprintf("统计 #%d", issue.Number))
+
+		issueType, status := l.Parse(issue.Labels)
+		if issueType == "" {
+			if registry, _ := s.FetchTypeRegistry(opts.Repo); registry != nil {
+				l = registry.applyTo(l)
+				issueType, status = l.Parse(issue.Labels)
+			}
+		}
+		if issueType == "" {
+			issueType = "unknown"
+		}
+		if report.Counts[issueType] == nil {
+			report.Counts[issueType] = map[string]int{}
+		}
+		report.Counts[issueType][status]++
+		report.Total++
+		switch status {
+		case "processed":
+			report.Processed++
+		case "rejected":
+			report.Rejected++
+		case "duplicate":
+			report.Duplicate++
+		}
+
+		changes, err := s.statusTimeline(owner, repo, l, issue.Number)
+		if err != nil {
+			return nil, err
+		}
+		comments, err := s.client.ListComments(owner, repo, issue.Number)
+		if err != nil {
+			return nil, err
+		}
+
+		if at, ok := firstResponseAt(issue.User.Login, comments, changes); ok {
+			firstResponse = append(firstResponse, at.Sub(created))
+		} else {
+			report.Unanswered++
+		}
+
+		spans, ongoing := processingSpans(changes)
+		processing = append(processing, spans...)
+		if ongoing && issue.State == "open" {
+			report.InProcessing++
+		}
+	}
+
+	report.FirstResponse = summarizeDurations(firstResponse)
+	report.Processing = summarizeDurations(processing)
+	if decided := report.Processed + report.Rejected; decided > 0 {
+		report.RejectionRate = math.Round(float64(report.Rejected)/float64(decided)*1000) / 1000
+	}
+
+	pending, err := s.pendingItems(opts, now)
+	if err != nil {
+		return nil, err
+	}
+	report.Pending = len(pending)
+	if len(pending) > 0 {
+		report.OldestPending = &pending[0]
+	}
+
+	if err := s.checkSLA(opts, report, pending); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// statusTimeline 从 Issue 事件中提取状态变化，按时间升序
+func (s *IssueService) statusTimeline(owner, repo string, l Labels, number int) ([]statusChange, error) {
+	events, err := s.client.ListIssueEvents(owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	var changes []statusChange
+	for _, e := range events {
+		at, err := time.Parse(time.RFC3339, e.CreatedAt)
+		if err != nil {
+			continue
+		}
+		switch {
+		case e.Event == "labeled" && e.Label != nil:
+			if status := l.StatusOf(e.Label.Name); status != "" {
+				changes = append(changes, statusChange{at: at, status: status})
+			}
+		case e.Event == "closed":
+			changes = append(changes, statusChange{at: at, status: "closed"})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].at.Before(changes[j].at) })
+	return changes, nil
+}
+
+// firstResponseAt 返回首次响应时间：非发起人的评论、离开 pending 或关闭中最早的一个
+func firstResponseAt(author string, comments []github.Comment, changes []statusChange) (time.Time, bool) {
+	var first time.Time
+	for _, c := range comments {
+		if c.User.Login == author {
+			continue
+		}
+		if at, err := time.Parse(time.RFC3339, c.CreatedAt); err == nil {
+			first = at
+			break
+		}
+	}
+	for _, c := range changes {
+		if c.status == "pending" {
+			continue
+		}
+		if first.IsZero() || c.at.Before(first) {
+			first = c.at
+		}
+		break
+	}
+	return first, !first.IsZero()
+}
+
+// processingSpans 返回已结束的 processing 阶段时长，ongoing 表示最后仍处于 processing
+func processingSpans(changes []statusChange) (spans []time.Duration, ongoing bool) {
+	var start time.Time
+	for _, c := range changes {
+		if c.status == "processing" {
+			if start.IsZero() {
+				start = c.at
+			}
+			continue
+		}
+		if !start.IsZero() {
+			spans = append(spans, c.at.Sub(start))
+			start = time.Time{}
+		}
+	}
+	return spans, !start.IsZero()
+}
+
+// pendingItems 返回当前全部 pending 的 Issue，按进入 pending 的时间升序（最久的在前）
+func (s *IssueService) pendingItems(opts StatsOptions, now time.Time) ([]PendingItem, error) {
+	owner, repo, _ := parseRepo(opts.Repo)
+	f := s.listFilter(ListOptions{Repo: opts.Repo, Status: "pending", Type: opts.Type})
+	issues, err := s.client.ListIssues(owner, repo, f.labels, f.state, 0)
+	if err != nil {
+		return nil, err
+	}
+	infos := s.toIssueInfos(opts.Repo, f.mapping, f.registryLoaded, issues)
+
+	items := make([]PendingItem, 0, len(issues))
+	for i, issue := range issues {
+		sinceAt, err := time.Parse(time.RFC3339, issue.CreatedAt)
+		if err != nil {
+			continue
+		}
+		// 从 needs-info 等状态恢复的 Issue 以最近一次进入 pending 的时间为准
+		changes, err := s.statusTimeline(owner, repo, f.mapping, issue.Number)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range changes {
+			if c.status == "pending" && c.at.After(sinceAt) {
+				sinceAt = c.at
+			}
+		}
+		items = append(items, PendingItem{
+			Number:   issue.Number,
+			Title:    issue.Title,
+			Type:     infos[i].Type,
+			URL:      issue.HTMLURL,
+			Since:    sinceAt.UTC().Format(time.RFC3339),
+			AgeHours: roundHours(now.Sub(sinceAt)),
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].AgeHours > items[j].AgeHours })
+	return items, nil
+}
+
+// checkSLA 按 MaxPendingAge 或 manifest 的 sla.first_response 检查 pending 时效，都未配置时跳过
+func (s *IssueService) checkSLA(opts StatsOptions, report *StatsReport, pending []PendingItem) error {
+	limit, source := opts.MaxPendingAge, "flag"
+	if limit == 0 {
+		manifest, err := s.FetchManifest(opts.Repo)
+		if err != nil {
+			return err
+		}
+		if manifest == nil || manifest.SLA == nil || manifest.SLA.FirstResponse == "" {
+			return nil
+		}
+		if limit, err = ParseDuration(manifest.SLA.FirstResponse); err != nil {
+			return fmt.Errorf("manifest 中的 sla.first_response 无效: %w", err)
+		}
+		source = "manifest"
+	}
+
+	sla := &SLAReport{MaxPendingAge: shortDuration(limit), Source: source, Breaches: []PendingItem{}}
+	for _, item := range pending {
+		if item.AgeHours >= limit.Hours() {
+			sla.Breaches = append(sla.Breaches, item)
+		}
+	}
+	report.SLA = sla
+	return nil
+}
+
+// summarizeDurations 计算平均值、中位数、P90 和最大值
+func summarizeDurations(ds []time.Duration) DurationStats {
+	if len(ds) == 0 {
+		return DurationStats{}
+	}
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	percentile := func(p float64) time.Duration {
+		return sorted[int(math.Ceil(p*float64(len(sorted))))-1]
+	}
+	return DurationStats{
+		Count:  len(sorted),
+		Mean:   roundHours(total / time.Duration(len(sorted))),
+		Median: roundHours(percentile(0.5)),
+		P90:    roundHours(percentile(0.9)),
+		Max:    roundHours(sorted[len(sorted)-1]),
+	}
+}
+
+// shortDuration 以 ParseDuration 接受的形式显示时长，如 3d、36h
+func shortDuration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	}
+	return d.String()
+}
+
+func roundHours(d time.Duration) float64 {
+	return math.Round(d.Hours()*10) / 10
+}
+
+// InboxStatuses 仍在收件箱中（Issue 打开）的状态
+var InboxStatuses = []string{"pending", "processing", "needs-info"}
+
+// InboxDepth 统计仓库中打开的 Issue 在各收件箱状态下的数量，没有 Issue 的状态为 0
+func (s *IssueService) InboxDepth(repoStr string) (map[string]int, error) {
+	owner, repo, err := parseRepo(repoStr)
+	if err != nil {
+		return nil, err
+	}
+	l := s.labelsFor(repoStr)
+	issues, err := s.client.ListIssues(owner, repo, []string{l.Marker}, "open", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	depth := make(map[string]int, len(InboxStatuses))
+	for _, status := range InboxStatuses {
+		depth[status] = 0
+	}
+	for _, issue := range issues {
+		_, status := l.Parse(issue.Labels)
+		if _, ok := depth[status]; ok {
+			depth[status]++
+		}
+	}
+	return depth, nil
+}
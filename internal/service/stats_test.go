@@ -0,0 +1,150 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shichao402/github-issue-pack/internal/github"
+)
+
+var statsBase = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// hoursLater 返回 statsBase 之后 h 小时的时间
+func hoursLater(h float64) time.Time {
+	return statsBase.Add(time.Duration(h * float64(time.Hour)))
+}
+
+func comment(author string, h float64) github.Comment {
+	return github.Comment{User: github.User{Login: author}, CreatedAt: hoursLater(h).Format(time.RFC3339)}
+}
+
+func change(status string, h float64) statusChange {
+	return statusChange{at: hoursLater(h), status: status}
+}
+
+func TestFirstResponseAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		comments []github.Comment
+		changes  []statusChange
+		want     float64 // 小时，-1 表示没有响应
+	}{
+		{
+			name: "没有响应",
+			comments: []github.Comment{
+				comment("reporter", 1),
+			},
+			changes: []statusChange{change("pending", 0)},
+			want:    -1,
+		},
+		{
+			name:     "忽略发起人的评论",
+			comments: []github.Comment{comment("reporter", 1), comment("maintainer", 5)},
+			want:     5,
+		},
+		{
+			name:     "状态变化早于评论",
+			comments: []github.Comment{comment("maintainer", 5)},
+			changes:  []statusChange{change("pending", 0), change("processing", 3), change("processed", 4)},
+			want:     3,
+		},
+		{
+			name:     "评论早于状态变化",
+			comments: []github.Comment{comment("maintainer", 2)},
+			changes:  []statusChange{change("needs-info", 6)},
+			want:     2,
+		},
+		{
+			name:    "直接关闭",
+			changes: []statusChange{change("pending", 0), change("closed", 8)},
+			want:    8,
+		},
+		{
+			name:     "忽略无法解析的时间",
+			comments: []github.Comment{{User: github.User{Login: "maintainer"}, CreatedAt: "bad"}, comment("other", 7)},
+			want:     7,
+		},
+	}
+	for _, tt := range tests {
+		at, ok := firstResponseAt("reporter", tt.comments, tt.changes)
+		if tt.want < 0 {
+			if ok {
+				t.Errorf("%s: firstResponseAt = %v, 期望没有响应", tt.name, at)
+			}
+			continue
+		}
+		if !ok || !at.Equal(hoursLater(tt.want)) {
+			t.Errorf("%s: firstResponseAt = %v, %v, 期望 %v", tt.name, at, ok, hoursLater(tt.want))
+		}
+	}
+}
+
+func TestProcessingSpans(t *testing.T) {
+	tests := []struct {
+		name        string
+		changes     []statusChange
+		wantSpans   []time.Duration
+		wantOngoing bool
+	}{
+		{
+			name:    "没有 processing",
+			changes: []statusChange{change("pending", 0), change("closed", 1)},
+		},
+		{
+			name:      "一次处理",
+			changes:   []statusChange{change("pending", 0), change("processing", 1), change("processed", 4)},
+			wantSpans: []time.Duration{3 * time.Hour},
+		},
+		{
+			name: "多次处理，重复的 processing 以第一次为准",
+			changes: []statusChange{
+				change("processing", 0), change("processing", 1), change("needs-info", 2),
+				change("processing", 10), change("closed", 11),
+			},
+			wantSpans: []time.Duration{2 * time.Hour, time.Hour},
+		},
+		{
+			name:        "仍在处理",
+			changes:     []statusChange{change("processing", 0), change("pending", 1), change("processing", 5)},
+			wantSpans:   []time.Duration{time.Hour},
+			wantOngoing: true,
+		},
+	}
+	for _, tt := range tests {
+		spans, ongoing := processingSpans(tt.changes)
+		if !reflect.DeepEqual(spans, tt.wantSpans) || ongoing != tt.wantOngoing {
+			t.Errorf("%s: processingSpans = %v, %v, 期望 %v, %v", tt.name, spans, ongoing, tt.wantSpans, tt.wantOngoing)
+		}
+	}
+}
+
+func TestSummarizeDurations(t *testing.T) {
+	hours := func(hs ...float64) []time.Duration {
+		var ds []time.Duration
+		for _, h := range hs {
+			ds = append(ds, time.Duration(h*float64(time.Hour)))
+		}
+		return ds
+	}
+
+	tests := []struct {
+		name string
+		in   []time.Duration
+		want DurationStats
+	}{
+		{name: "空", in: nil, want: DurationStats{}},
+		{name: "单个", in: hours(2.25), want: DurationStats{Count: 1, Mean: 2.3, Median: 2.3, P90: 2.3, Max: 2.3}},
+		{name: "乱序输入", in: hours(4, 1, 3, 2), want: DurationStats{Count: 4, Mean: 2.5, Median: 2, P90: 4, Max: 4}},
+		{
+			name: "十个值",
+			in:   hours(10, 9, 8, 7, 6, 5, 4, 3, 2, 1),
+			want: DurationStats{Count: 10, Mean: 5.5, Median: 5, P90: 9, Max: 10},
+		},
+	}
+	for _, tt := range tests {
+		if got := summarizeDurations(tt.in); got != tt.want {
+			t.Errorf("%s: summarizeDurations = %+v, 期望 %+v", tt.name, got, tt.want)
+		}
+	}
+}
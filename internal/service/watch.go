@@ -0,0 +1,146 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WatchState watch 的轮询游标，保存在本地状态文件中以便重启后继续
+type WatchState struct {
+	Repo   string         `json:"repo"`
+	Since  string         `json:"since,omitempty"` // 已看到的最大 updated_at
+	ETag   string         `json:"etag,omitempty"`  // 上次轮询第一页的 ETag
+	Issues map[int]string `json:"issues"`          // Issue 编号 → 上次看到的状态
+}
+
+// 事件类型
+const (
+	WatchEventNew           = "new"
+	WatchEventStatusChanged = "status_changed"
+)
+
+// WatchEvent 新 Issue 或状态变更事件
+type WatchEvent struct {
+	Event          string `json:"event"`
+	Repo           string `json:"repo"`
+	Number         int    `json:"number"`
+	Title          string `json:"title"`
+	Type           string `json:"type"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status,omitempty"`
+	URL            string `json:"url"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+// LoadWatchState 读取状态文件，文件不存在时返回空状态
+func LoadWatchState(path, repo string) (*WatchState, error) {
+	state := &WatchState{Repo: repo, Issues: map[int]string{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取状态文件失败: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("解析状态文件失败: %w", err)
+	}
+	if state.Repo != repo {
+		return nil, fmt.Errorf("状态文件 %s 属于仓库 %s，不是 %s", path, state.Repo, repo)
+	}
+	if state.Issues == nil {
+		state.Issues = map[int]string{}
+	}
+	return state, nil
+}
+
+// Save 写入状态文件（先写临时文件再重命名，避免中断时损坏）
+func (st *WatchState) Save(path string) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建状态目录失败: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("写入状态文件失败: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// InboxDepth 按上次看到的状态统计各收件箱状态下的 Issue 数量，不需要额外的 API 调用
+func (st *WatchState) InboxDepth() map[string]int {
+	depth := make(map[string]int, len(InboxStatuses))
+	for _, status := range InboxStatuses {
+		depth[status] = 0
+	}
+	for _, status := range st.Issues {
+		if _, ok := depth[status]; ok {
+			depth[status]++
+		}
+	}
+	return depth
+}
+
+// Poll 获取上次轮询之后更新过的 Issue，返回新 Issue 和状态变更事件并更新游标
+//
+// 状态为空（首次运行）时 emitExisting 为 false 则只记录现有 Issue，不产生事件。
+func (s *IssueService) Poll(st *WatchState, emitExisting bool) ([]WatchEvent, error) {
+	owner, repo, err := parseRepo(st.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	f := s.listFilter(ListOptions{Repo: st.Repo, Status: "all"})
+	issues, etag, notModified, err := s.client.ListIssuesSince(owner, repo, f.labels, f.state, st.Since, st.ETag)
+	if err != nil {
+		return nil, err
+	}
+	if notModified {
+		return nil, nil
+	}
+
+	baseline := st.Since == "" && !emitExisting
+	infos := s.toIssueInfos(st.Repo, f.mapping, f.registryLoaded, issues)
+
+	var events []WatchEvent
+	for i, info := range infos {
+		updatedAt := issues[i].UpdatedAt
+		previous, seen := st.Issues[info.Number]
+		st.Issues[info.Number] = info.Status
+		if updatedAt > st.Since {
+			st.Since = updatedAt
+		}
+		if baseline || (seen && previous == info.Status) {
+			continue
+		}
+
+		event := WatchEvent{
+			Event:     WatchEventNew,
+			Repo:      st.Repo,
+			Number:    info.Number,
+			Title:     info.Title,
+			Type:      info.Type,
+			Status:    info.Status,
+			URL:       info.URL,
+			UpdatedAt: updatedAt,
+		}
+		if seen {
+			event.Event = WatchEventStatusChanged
+			event.PreviousStatus = previous
+		}
+		events = append(events, event)
+	}
+
+	if st.Since == "" {
+		// 仓库中还没有 Issue，从当前时间开始
+		st.Since = time.Now().UTC().Format(time.RFC3339)
+	}
+	st.ETag = etag
+	return events, nil
+}